@@ -0,0 +1,103 @@
+// This file provides additional information about layer parts, which is stored in their attributes.
+
+package clip
+
+import "GoSlice/data"
+
+const (
+	// depthAttribute is the key of the nesting depth in the attributes of a layer part.
+	depthAttribute = "depth"
+
+	// roleAttribute is the key of the wall role in the attributes of a layer part.
+	roleAttribute = "wallRole"
+
+	// idAttribute is the key of the stable id in the attributes of a layer part.
+	idAttribute = "partID"
+)
+
+// attributedLayerPart is a layer part with additional attributes.
+// It keeps its own extruder, so that changing it does not change the wrapped part.
+type attributedLayerPart struct {
+	data.LayerPart
+	attributes map[string]interface{}
+	extruder   int
+}
+
+// withAttribute returns the part with the given value added to its attributes.
+// The attributes and the extruder of the original part are copied and not modified,
+// also not by a later SetExtruder on the result.
+func withAttribute(part data.LayerPart, key string, value interface{}) *attributedLayerPart {
+	attributes := map[string]interface{}{}
+	for k, v := range part.Attributes() {
+		attributes[k] = v
+	}
+	attributes[key] = value
+
+	return &attributedLayerPart{
+		LayerPart:  part,
+		attributes: attributes,
+		extruder:   part.Extruder(),
+	}
+}
+
+func (p *attributedLayerPart) Attributes() map[string]interface{} {
+	return p.attributes
+}
+
+func (p *attributedLayerPart) Extruder() int {
+	return p.extruder
+}
+
+func (p *attributedLayerPart) SetExtruder(extruder int) {
+	p.extruder = extruder
+}
+
+// WithNestingDepth records the nesting depth of each generated part in its attributes,
+// which can be read by NestingDepth.
+// Without it the parts are the same, but the depth is not available.
+//
+// It is used by GenerateLayerParts.
+func WithNestingDepth() Option {
+	return func(o *options) {
+		o.nestingDepth = true
+	}
+}
+
+// NestingDepth returns how deep the part is nested in the holes of other parts.
+// Parts which are not inside of a hole have the depth 0, an island inside of the hole of such a part has the depth 1 and so on.
+// This can be used e.g. to print islands inside of holes differently.
+//
+// The depth is only available if the part was generated by GenerateLayerParts using the WithNestingDepth option
+// (the modifiers keep the attributes of the parts).
+// Otherwise ok is false.
+func NestingDepth(part data.LayerPart) (depth int, ok bool) {
+	depth, ok = part.Attributes()[depthAttribute].(int)
+	return depth, ok
+}
+
+// WallRole describes which wall of a part an inset part is.
+type WallRole int
+
+const (
+	// OuterWall is the outermost wall which is visible on the surface of the print.
+	OuterWall WallRole = iota
+
+	// InnerWall is any wall inside of the outer wall.
+	InnerWall
+)
+
+// String returns a readable name of the role.
+func (r WallRole) String() string {
+	if r == OuterWall {
+		return "outer wall"
+	}
+	return "inner wall"
+}
+
+// Role returns the role of an inset part, which is set by Inset, InsetLayer and InsetWithOrigin.
+// It can be used to choose e.g. the speed and flow of a wall without relying on its position in the result.
+// If the part has no role, ok is false.
+func Role(part data.LayerPart) (role WallRole, ok bool) {
+	role, ok = part.Attributes()[roleAttribute].(WallRole)
+	return role, ok
+}
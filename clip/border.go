@@ -0,0 +1,60 @@
+// This file provides a border loop around the infill of a part.
+
+package clip
+
+import (
+	"GoSlice/data"
+	"context"
+)
+
+// bordered wraps a pattern and prints a loop around the filled region before the pattern.
+type bordered struct {
+	pattern   Pattern
+	lineWidth data.Micrometer
+}
+
+// NewBorderedPattern wraps the given pattern and adds one loop around the filled part before the lines of the pattern.
+// The loop gives the infill a clean edge and keeps the lines from pulling inward.
+//
+// The loop is placed half a line width inside of the outline (and outside of the holes).
+// The pattern only fills the region inside of the loop, so the lines don't overlap the loop,
+// except for the overlap which is configured for the pattern itself.
+// If the part is too small for the loop, it is filled by the pattern only.
+func NewBorderedPattern(pattern Pattern, lineWidth data.Micrometer) Pattern {
+	return bordered{
+		pattern:   pattern,
+		lineWidth: lineWidth,
+	}
+}
+
+// Fill implements the Pattern interface by adding a loop around the lines of the wrapped pattern.
+// The loops are closed by repeating the first point at the end of each path.
+func (p bordered) Fill(ctx context.Context, layerNr int, part data.LayerPart) (data.Paths, error) {
+	if err := validateParts([]data.LayerPart{part}); err != nil {
+		return nil, err
+	}
+
+	loops := offsetParts([]data.LayerPart{part}, -p.lineWidth/2)
+	if len(loops) == 0 {
+		return p.pattern.Fill(ctx, layerNr, part)
+	}
+
+	var result data.Paths
+	for _, loop := range loops {
+		result = append(result, closedLoop(loop.Outline()))
+		for _, hole := range loop.Holes() {
+			result = append(result, closedLoop(hole))
+		}
+	}
+
+	// the inner edge of the loop is one line width inside of the part
+	for _, interior := range offsetParts([]data.LayerPart{part}, -p.lineWidth) {
+		lines, err := p.pattern.Fill(ctx, layerNr, interior)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, lines...)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,159 @@
+// This file provides the chaining of infill lines to reduce the travel moves.
+
+package clip
+
+import (
+	"GoSlice/data"
+	"context"
+)
+
+// chained wraps a pattern and reorders its lines using nearest neighbor chaining.
+type chained struct {
+	pattern Pattern
+}
+
+// ChainedPaths is the chained output of a ChainingPattern.
+// It contains the lines and the connector moves between them in print order.
+type ChainedPaths struct {
+	Paths data.Paths
+
+	// Travel has one entry per path and is true for the connector moves.
+	// A connector goes from the end of the previous line to the start of the next line
+	// and is a travel move without extrusion.
+	Travel []bool
+}
+
+// Lines returns only the lines of the chained output without the connector moves.
+func (c ChainedPaths) Lines() data.Paths {
+	var lines data.Paths
+	for i, path := range c.Paths {
+		if !c.Travel[i] {
+			lines = append(lines, path)
+		}
+	}
+
+	return lines
+}
+
+// ChainingPattern is a Pattern which can also provide its lines as one chain
+// in which the connector moves are marked as travel moves.
+type ChainingPattern interface {
+	Pattern
+
+	// FillChained fills the part like Fill, but also returns the connector moves between the lines.
+	FillChained(ctx context.Context, layerNr int, part data.LayerPart) (ChainedPaths, error)
+}
+
+// NewChainedPattern wraps the given pattern and orders its lines greedily:
+// After each line the nearest unused line is used next, which is reversed if its end is nearer than its start.
+// This minimizes the travel moves even if the connection crosses open space,
+// so it also works for patterns where the line ends are scattered.
+//
+// FillChained returns the chain including the connector moves, flagged as travel,
+// while Fill only returns the reordered lines.
+// As the print order changes, the appearance of the infill may change, too.
+func NewChainedPattern(pattern Pattern) ChainingPattern {
+	return chained{
+		pattern: pattern,
+	}
+}
+
+// Fill implements the Pattern interface by chaining the lines of the wrapped pattern.
+func (p chained) Fill(ctx context.Context, layerNr int, part data.LayerPart) (data.Paths, error) {
+	chain, err := p.FillChained(ctx, layerNr, part)
+	if err != nil {
+		return nil, err
+	}
+
+	return chain.Lines(), nil
+}
+
+// FillChained implements the ChainingPattern interface.
+// Between each two lines a connector move from the end of the first line to the start of the second one is added.
+func (p chained) FillChained(ctx context.Context, layerNr int, part data.LayerPart) (ChainedPaths, error) {
+	paths, err := p.pattern.Fill(ctx, layerNr, part)
+	if err != nil {
+		return ChainedPaths{}, err
+	}
+
+	lines, err := chainPaths(ctx, paths)
+	if err != nil {
+		return ChainedPaths{}, err
+	}
+
+	var chain ChainedPaths
+	var previous data.Path
+	for _, line := range lines {
+		if len(line) == 0 {
+			continue
+		}
+		if previous != nil {
+			chain.Paths = append(chain.Paths, data.Path{previous[len(previous)-1], line[0]})
+			chain.Travel = append(chain.Travel, true)
+		}
+		chain.Paths = append(chain.Paths, line)
+		chain.Travel = append(chain.Travel, false)
+		previous = line
+	}
+
+	return chain, nil
+}
+
+// chainPaths orders the open paths by always continuing with the path whose start or end is nearest
+// to the end of the previous path. It starts with the first path.
+// If the context gets cancelled, the context error is returned.
+func chainPaths(ctx context.Context, paths data.Paths) (data.Paths, error) {
+	var unused data.Paths
+	for _, path := range paths {
+		if len(path) > 0 {
+			unused = append(unused, path)
+		}
+	}
+
+	if len(unused) == 0 {
+		return paths, nil
+	}
+
+	result := data.Paths{unused[0]}
+	unused = unused[1:]
+
+	for len(unused) > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		last := result[len(result)-1]
+		end := last[len(last)-1]
+
+		best := 0
+		bestReversed := false
+		var bestDistance data.Micrometer = -1
+		for i, path := range unused {
+			if d := end.Sub(path[0]).Size(); bestDistance == -1 || d < bestDistance {
+				best, bestReversed, bestDistance = i, false, d
+			}
+			if d := end.Sub(path[len(path)-1]).Size(); d < bestDistance {
+				best, bestReversed, bestDistance = i, true, d
+			}
+		}
+
+		next := unused[best]
+		if bestReversed {
+			next = reversedMicroPath(next)
+		}
+		result = append(result, next)
+		unused = append(unused[:best], unused[best+1:]...)
+	}
+
+	return result, nil
+}
+
+// reversedMicroPath returns a reversed copy of the path.
+func reversedMicroPath(path data.Path) data.Path {
+	result := make(data.Path, len(path))
+	for i, point := range path {
+		result[len(path)-1-i] = point
+	}
+
+	return result
+}
@@ -4,6 +4,8 @@ package clip
 
 import (
 	"GoSlice/data"
+	"context"
+	"sort"
 
 	clipper "github.com/aligator/go.clipper"
 )
@@ -12,14 +14,47 @@ import (
 type Pattern interface {
 	// Fill fills the given part.
 	// It returns the final infill pattern.
-	Fill(layerNr int, part data.LayerPart) data.Paths
+	// The line width and spacing are set when creating the pattern,
+	// so use a separate pattern for layers with a different line width (e.g. the first layer).
+	// If the context gets cancelled, the context error is returned.
+	// If a coordinate of the part exceeds MaxCoordinate, a *CoordinateError is returned.
+	Fill(ctx context.Context, layerNr int, part data.LayerPart) (data.Paths, error)
+}
+
+// FillParts fills all given parts using the pattern.
+// In contrast to joining the results of Pattern.Fill, the lines stay grouped by their part:
+// result[i] contains the lines of parts[i], so e.g. the extruder of the part can be used for its lines.
+// Parts without lines get an empty group.
+// If the context gets cancelled, the context error is returned.
+func FillParts(ctx context.Context, pattern Pattern, layerNr int, parts []data.LayerPart) ([]data.Paths, error) {
+	result := make([]data.Paths, len(parts))
+	for i, part := range parts {
+		paths, err := pattern.Fill(ctx, layerNr, part)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = paths
+	}
+
+	return result, nil
 }
 
 // Clipper is an interface that provides methods needed by GoSlice to clip and alter polygons.
 type Clipper interface {
 	// GenerateLayerParts partitions the whole layer into several partition parts.
 	// Each of them describes a polygon with holes.
-	GenerateLayerParts(l data.Layer) (data.PartitionedLayer, bool)
+	// The parts are returned in a deterministic order which does not depend on the order of the input polygons.
+	// If the context gets cancelled, the context error is returned.
+	// If clipper fails, a *ClipError is returned.
+	// If a coordinate of the layer exceeds MaxCoordinate, a *CoordinateError is returned.
+	//
+	// Self-intersecting polygons can be resolved before the union by passing the WithCleanup option.
+	// The input polygons are simplified, the WithPointFilter option can be used to change how this is done.
+	// The fill type of the union can be set by the WithFillType option and defaults to EvenOdd.
+	// Statistics about the discarded geometry can be collected by the WithStats option.
+	// The nesting depth of the parts can be recorded by the WithNestingDepth option.
+	// All parts are assigned to the extruder 0, use SetExtruder to print them with another one.
+	GenerateLayerParts(ctx context.Context, l data.Layer, opts ...Option) (data.PartitionedLayer, error)
 
 	// InsetLayer returns all new paths generated by insetting all parts of the layer.
 	// The result is built the following way: [part][insetNr][insetParts]data.LayerPart
@@ -30,8 +65,27 @@ type Clipper interface {
 	//    and all following are from holes inside of the polygon.
 	// The array for a part may be empty.
 	//
+	// The offset is the line width: the outer wall is placed offset/2 inside of the outline
+	// and each further wall offset further inside.
+	// To use a different line width for a layer (e.g. a wider first layer), just pass its width as offset.
 	// If you need to ex-set a part, just provide a negative offset.
-	InsetLayer(layer []data.LayerPart, offset data.Micrometer, insetCount int) [][][]data.LayerPart
+	// To grow or shrink all walls by a fixed amount (e.g. for elephant foot compensation) use the WithExpansion option.
+	// The resulting parts keep the extruder of the part they are created from.
+	// All walls are closed loops, which is reported by IsClosed of the resulting parts.
+	// If the context gets cancelled, the context error is returned.
+	// If a coordinate of a part exceeds MaxCoordinate, a *CoordinateError is returned.
+	InsetLayer(ctx context.Context, layer []data.LayerPart, offset data.Micrometer, insetCount int, opts ...Option) ([][][]data.LayerPart, error)
+
+	// InsetLayerFunc insets all parts of the layer the same way as InsetLayer,
+	// but passes the insets ([insetNr][insetParts]data.LayerPart) of each part to the yield function
+	// as soon as they are calculated instead of collecting them for the whole layer.
+	// This keeps the memory bounded for layers with many parts and walls.
+	//
+	// The parts are yielded one after another in the order of the layer.
+	// If yield returns an error, no further parts are inset and the error is returned.
+	// If the context gets cancelled, the context error is returned.
+	// If a coordinate of a part exceeds MaxCoordinate, a *CoordinateError is returned.
+	InsetLayerFunc(ctx context.Context, layer []data.LayerPart, offset data.Micrometer, insetCount int, yield PartInsetsFunc, opts ...Option) error
 
 	// Inset insets the given layer part.
 	// The result is built the following way: [insetNr][insetParts]data.LayerPart
@@ -41,22 +95,60 @@ type Clipper interface {
 	//    and all following are from holes inside of the polygon.
 	// The array for a part may be empty.
 	//
+	// The offset is the line width: the outer wall is placed offset/2 inside of the outline
+	// and each further wall offset further inside.
+	// To use a different line width for a layer (e.g. a wider first layer), just pass its width as offset.
 	// If you need to ex-set a part, just provide a negative offset.
-	Inset(part data.LayerPart, offset data.Micrometer, insetCount int) [][]data.LayerPart
+	// To grow or shrink all walls by a fixed amount (e.g. for elephant foot compensation) use the WithExpansion option.
+	// The resulting parts keep the extruder of the part they are created from.
+	// All walls are closed loops, which is reported by IsClosed of the resulting parts.
+	// Each resulting part is tagged with its WallRole, which can be read by Role.
+	// The coordinates of the part are not validated, use InsetLayer to get a *CoordinateError for too large coordinates.
+	//
+	// Rings (parts with holes) whose band gets thinner than one wall get no further walls,
+	// as the walls of the outline and the holes would overlap.
+	// If this already happens at the outer wall, it is kept anyway, so that the ring does not get lost.
+	// Use the WithThinRingCenterLine option to print a single loop in the middle of such a band instead.
+	Inset(part data.LayerPart, offset data.Micrometer, insetCount int, opts ...Option) [][]data.LayerPart
+
+	// InsetWithOrigin insets the given layer part the same way as Inset,
+	// but groups the walls of each inset by the path of the part they were created from.
+	// The result is built the following way: [insetNr][origin]OriginPaths
+	//
+	// The outline group comes first, followed by the hole groups in the order of the holes.
+	// Groups without walls are left out, so if a hole vanishes at a deeper inset, its group just disappears.
+	InsetWithOrigin(part data.LayerPart, offset data.Micrometer, insetCount int, opts ...Option) [][]OriginPaths
+
+	// Offset moves the closed polygons by the signed distance using the given join type.
+	// A positive distance grows the polygons, a negative shrinks them.
+	// Holes have to be oriented opposite to the outlines, so that they shrink while the outlines grow.
+	// Polygons which collapse by the offset are dropped.
+	// It is the primitive all other offsets (e.g. Inset or the infill overlap) are based on.
+	//
+	// The arc tolerance of RoundJoin can be set by the WithArcTolerance option.
+	// If a coordinate of the paths exceeds MaxCoordinate, a *CoordinateError is returned.
+	Offset(paths data.Paths, distance data.Micrometer, join JoinType, opts ...Option) (data.Paths, error)
 
 	// Difference calculates the difference between the parts and the toRemove parts.
 	// It returns the result as a new slice of layer parts.
-	Difference(parts []data.LayerPart, toRemove []data.LayerPart) (clippedParts []data.LayerPart, ok bool)
+	// The fill type can be set by the WithFillType option and defaults to EvenOdd.
+	// If a coordinate exceeds MaxCoordinate, ok is false.
+	Difference(parts []data.LayerPart, toRemove []data.LayerPart, opts ...Option) (clippedParts []data.LayerPart, ok bool)
 
 	// Intersection calculates the intersection between the parts and the toIntersect parts.
 	// It returns the result as a new slice of layer parts.
-	Intersection(parts []data.LayerPart, toIntersect []data.LayerPart) (clippedParts []data.LayerPart, ok bool)
+	// The fill type can be set by the WithFillType option and defaults to EvenOdd.
+	// If a coordinate exceeds MaxCoordinate, ok is false.
+	Intersection(parts []data.LayerPart, toIntersect []data.LayerPart, opts ...Option) (clippedParts []data.LayerPart, ok bool)
 
 	// Union calculates the union of the parts and the toMerge parts.
 	// It returns the result as a new slice of layer parts.
-	Union(parts []data.LayerPart, toIntersect []data.LayerPart) (clippedParts []data.LayerPart, ok bool)
+	// The fill type can be set by the WithFillType option and defaults to EvenOdd.
+	// If a coordinate exceeds MaxCoordinate, ok is false.
+	Union(parts []data.LayerPart, toIntersect []data.LayerPart, opts ...Option) (clippedParts []data.LayerPart, ok bool)
 
 	// IsCrossingPerimeter checks if the given line crosses any perimeter of the given parts. If yes, the result is true.
+	// If a coordinate exceeds MaxCoordinate, ok is false.
 	IsCrossingPerimeter(parts []data.LayerPart, line data.Path) (result, ok bool)
 }
 
@@ -68,8 +160,55 @@ func NewClipper() Clipper {
 	return &clipperClipper{}
 }
 
+// MaxCoordinate is the largest absolute coordinate which can be processed safely by clipper.
+// Clipper multiplies coordinates internally, so larger coordinates may overflow
+// its 64 bit integer arithmetic and silently corrupt the geometry.
+// As GoSlice uses micrometers, models are limited to about ±1073 meters around the origin,
+// which is far more than any printer can print.
+const MaxCoordinate data.Micrometer = 0x3FFFFFFF
+
+// validateCoordinates checks that no point of the path exceeds MaxCoordinate.
+func validateCoordinates(p data.Path) error {
+	for _, point := range p {
+		if point.X() > MaxCoordinate || point.X() < -MaxCoordinate || point.Y() > MaxCoordinate || point.Y() < -MaxCoordinate {
+			return &CoordinateError{Point: point}
+		}
+	}
+
+	return nil
+}
+
+// validatePaths checks that no point of the paths exceeds MaxCoordinate.
+func validatePaths(paths data.Paths) error {
+	for _, path := range paths {
+		if err := validateCoordinates(path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateParts checks that no point of the outlines and holes of the parts exceeds MaxCoordinate.
+func validateParts(parts []data.LayerPart) error {
+	for _, part := range parts {
+		if err := validateCoordinates(part.Outline()); err != nil {
+			return err
+		}
+		if err := validatePaths(part.Holes()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // clipperPoint converts the GoSlice point representation to the
 // representation which is used by the external clipper lib.
+// The coordinates are used 1:1, so they must not exceed MaxCoordinate.
+// The conversion itself does not check this, instead the exported functions validate their input
+// and return a *CoordinateError (or ok = false) if a coordinate is out of range.
+// Only Inset and InsetWithOrigin, which can not return an error, pass their input unchecked.
 func clipperPoint(p data.MicroPoint) *clipper.IntPoint {
 	return &clipper.IntPoint{
 		X: clipper.CInt(p.X()),
@@ -79,8 +218,13 @@ func clipperPoint(p data.MicroPoint) *clipper.IntPoint {
 
 // clipperPaths converts the GoSlice Paths representation
 // to the representation which is used by the external clipper lib.
+// Empty paths result in nil without allocating anything.
 func clipperPaths(p data.Paths) clipper.Paths {
-	var result clipper.Paths
+	if len(p) == 0 {
+		return nil
+	}
+
+	result := make(clipper.Paths, 0, len(p))
 	for _, path := range p {
 		result = append(result, clipperPath(path))
 	}
@@ -91,7 +235,11 @@ func clipperPaths(p data.Paths) clipper.Paths {
 // clipperPath converts the GoSlice Path representation
 // to the representation which is used by the external clipper lib.
 func clipperPath(p data.Path) clipper.Path {
-	var result clipper.Path
+	if len(p) == 0 {
+		return nil
+	}
+
+	result := make(clipper.Path, 0, len(p))
 	for _, point := range p {
 		result = append(result, clipperPoint(point))
 	}
@@ -110,7 +258,11 @@ func microPoint(p *clipper.IntPoint) data.MicroPoint {
 // The parameter simplify enables simplifying of the path using
 // the default simplification settings.
 func microPath(p clipper.Path, simplify bool) data.Path {
-	var result data.Path
+	if len(p) == 0 {
+		return nil
+	}
+
+	result := make(data.Path, 0, len(p))
 	for _, point := range p {
 		result = append(result, microPoint(point))
 	}
@@ -133,37 +285,176 @@ func microPaths(p clipper.Paths, simplify bool) data.Paths {
 	return result
 }
 
-func (c clipperClipper) GenerateLayerParts(l data.Layer) (data.PartitionedLayer, bool) {
+func (c clipperClipper) GenerateLayerParts(ctx context.Context, l data.Layer, opts ...Option) (data.PartitionedLayer, error) {
+	o := newOptions(opts...)
+
+	stats := LayerPartsStats{}
+	if o.stats != nil {
+		defer func() {
+			*o.stats = stats
+		}()
+	}
+
 	polyList := clipper.Paths{}
 	// convert all polygons to clipper polygons
 	for _, layerPolygon := range l.Polygons() {
-		polyList = append(polyList, clipperPath(layerPolygon.Simplify(-1, -1)))
+		stats.InputPolygons++
+
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if err := validateCoordinates(layerPolygon); err != nil {
+			return nil, err
+		}
+
+		var polygon clipper.Path
+		if o.pointFilter {
+			polygon = clipperPath(layerPolygon.FilterNearPoints(o.pointFilterDistance, o.pointFilterDeviation))
+		} else {
+			polygon = clipperPath(layerPolygon.Simplify(-1, -1))
+		}
+
+		stats.FilteredPoints += len(layerPolygon) - len(polygon)
+		if len(polygon) < 3 {
+			stats.DegeneratePolygons++
+		}
+
+		if o.cleanup {
+			polyList = append(polyList, resolveSelfIntersections(polygon, o.cleanupFillType)...)
+		} else {
+			polyList = append(polyList, polygon)
+		}
 	}
 
 	if len(polyList) == 0 {
-		return data.NewPartitionedLayer([]data.LayerPart{}), true
+		return data.NewPartitionedLayer([]data.LayerPart{}), nil
 	}
 
 	cl := clipper.NewClipper(clipper.IoNone)
 	cl.AddPaths(polyList, clipper.PtSubject, true)
-	resultPolys, ok := cl.Execute2(clipper.CtUnion, clipper.PftEvenOdd, clipper.PftEvenOdd)
+	resultPolys, ok := cl.Execute2(clipper.CtUnion, o.fillType.clipperFillType(), o.fillType.clipperFillType())
 	if !ok {
-		return nil, false
+		return nil, newClipError(clipper.CtUnion, polyList)
+	}
+
+	parts, depths, err := polyTreeToNestedLayerParts(ctx, resultPolys)
+	if err != nil {
+		return nil, err
+	}
+	stats.OutputParts = len(parts)
+
+	if o.nestingDepth {
+		for i, part := range parts {
+			parts[i] = withAttribute(part, depthAttribute, depths[i])
+		}
+	}
+
+	return data.NewPartitionedLayer(sortLayerParts(parts)), nil
+}
+
+// resolveSelfIntersections splits a possibly self-intersecting polygon into simple polygons using the given fill type.
+// The orientation of the input polygon is kept for all resulting polygons, so holes stay holes.
+func resolveSelfIntersections(polygon clipper.Path, fillType FillType) clipper.Paths {
+	if len(polygon) < 3 {
+		return clipper.Paths{polygon}
+	}
+
+	cl := clipper.NewClipper(clipper.IoNone)
+	orientation := clipper.Orientation(polygon)
+
+	// Each polygon is simplified as if it was counter clockwise,
+	// so that the fill type behaves the same for outlines and holes.
+	if !orientation {
+		polygon = reversedPath(polygon)
+	}
+	simplified := cl.SimplifyPolygon(polygon, fillType.clipperFillType())
+
+	if !orientation {
+		for i, path := range simplified {
+			simplified[i] = reversedPath(path)
+		}
 	}
 
-	return data.NewPartitionedLayer(polyTreeToLayerParts(resultPolys)), true
+	return simplified
+}
+
+// reversedPath returns a copy of the path with the reversed order of points.
+func reversedPath(path clipper.Path) clipper.Path {
+	reversed := make(clipper.Path, len(path))
+	for i, point := range path {
+		reversed[len(path)-1-i] = point
+	}
+	return reversed
+}
+
+// sortLayerParts sorts the given parts by a stable key so that the order
+// does not depend on the order of the input polygons or the poly tree traversal.
+// The parts are ordered by the min corner of their bounding box (first X, then Y)
+// and if these are equal by the area of their outline.
+// The given slice is sorted in place and returned for convenience.
+func sortLayerParts(parts []data.LayerPart) []data.LayerPart {
+	type sortablePart struct {
+		part data.LayerPart
+		min  data.MicroPoint
+		area float64
+	}
+
+	sortable := make([]sortablePart, len(parts))
+	for i, part := range parts {
+		min, _ := part.Outline().Bounds()
+		sortable[i] = sortablePart{
+			part: part,
+			min:  min,
+			area: clipper.Area(clipperPath(part.Outline())),
+		}
+	}
+
+	sort.SliceStable(sortable, func(i, j int) bool {
+		a, b := sortable[i], sortable[j]
+		if a.min.X() != b.min.X() {
+			return a.min.X() < b.min.X()
+		}
+		if a.min.Y() != b.min.Y() {
+			return a.min.Y() < b.min.Y()
+		}
+		return a.area < b.area
+	})
+
+	for i, s := range sortable {
+		parts[i] = s.part
+	}
+
+	return parts
 }
 
 // polyTreeToLayerParts creates layer parts out of a poly tree (which is the result of clipper's Execute2).
 func polyTreeToLayerParts(tree *clipper.PolyTree) []data.LayerPart {
+	// The background context is never cancelled, so no error can occur.
+	layerParts, _ := polyTreeToLayerPartsContext(context.Background(), tree)
+	return layerParts
+}
+
+// polyTreeToLayerPartsContext is the same as polyTreeToLayerParts but it stops
+// and returns the context error as soon as the given context gets cancelled.
+func polyTreeToLayerPartsContext(ctx context.Context, tree *clipper.PolyTree) ([]data.LayerPart, error) {
+	layerParts, _, err := polyTreeToNestedLayerParts(ctx, tree)
+	return layerParts, err
+}
+
+// polyTreeToNestedLayerParts is the same as polyTreeToLayerPartsContext but it also returns the nesting depth of each part.
+// The parts which are not inside of a hole have the depth 0, parts inside of their holes the depth 1 and so on.
+func polyTreeToNestedLayerParts(ctx context.Context, tree *clipper.PolyTree) ([]data.LayerPart, []int, error) {
 	var layerParts []data.LayerPart
+	var depths []int
 
 	var polysForNextRound []*clipper.PolyNode
 
 	for _, c := range tree.Childs() {
 		polysForNextRound = append(polysForNextRound, c)
 	}
-	for {
+	// each round is one nesting level deeper
+	for depth := 0; ; depth++ {
 		if polysForNextRound == nil {
 			break
 		}
@@ -171,6 +462,10 @@ func polyTreeToLayerParts(tree *clipper.PolyTree) []data.LayerPart {
 		polysForNextRound = nil
 
 		for _, p := range thisRound {
+			if err := ctx.Err(); err != nil {
+				return nil, nil, err
+			}
+
 			var holes data.Paths
 
 			for _, child := range p.Childs() {
@@ -183,53 +478,139 @@ func polyTreeToLayerParts(tree *clipper.PolyTree) []data.LayerPart {
 
 			// TODO: simplify, yes / no ??
 			layerParts = append(layerParts, data.NewBasicLayerPart(microPath(p.Contour(), false), holes))
+			depths = append(depths, depth)
 		}
 	}
 
-	return layerParts
+	return layerParts, depths, nil
+}
+
+// offsetParts offsets all given parts by the given distance.
+// A negative distance shrinks the parts, a positive grows them.
+// Parts which collapse by the offset are dropped.
+func offsetParts(parts []data.LayerPart, distance data.Micrometer) []data.LayerPart {
+	if len(parts) == 0 {
+		return nil
+	}
+
+	return offsetToParts(partsToClipperPaths(parts), float64(distance), SquareJoin, options{})
 }
 
-func (c clipperClipper) InsetLayer(layer []data.LayerPart, offset data.Micrometer, insetCount int) [][][]data.LayerPart {
+// PartInsetsFunc is called by InsetLayerFunc with the insets ([insetNr][insetParts]data.LayerPart) of each part.
+// The partNr is the index of the part in the layer.
+type PartInsetsFunc func(partNr int, insets [][]data.LayerPart) error
+
+func (c clipperClipper) InsetLayer(ctx context.Context, layer []data.LayerPart, offset data.Micrometer, insetCount int, opts ...Option) ([][][]data.LayerPart, error) {
 	var result [][][]data.LayerPart
-	for _, part := range layer {
-		result = append(result, c.Inset(part, offset, insetCount))
+	err := c.InsetLayerFunc(ctx, layer, offset, insetCount, func(partNr int, insets [][]data.LayerPart) error {
+		result = append(result, insets)
+		return nil
+	}, opts...)
+	if err != nil {
+		return nil, err
 	}
 
-	return result
+	return result, nil
 }
 
-func (c clipperClipper) Inset(part data.LayerPart, offset data.Micrometer, insetCount int) [][]data.LayerPart {
+func (c clipperClipper) InsetLayerFunc(ctx context.Context, layer []data.LayerPart, offset data.Micrometer, insetCount int, yield PartInsetsFunc, opts ...Option) error {
+	for partNr, part := range layer {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := validateParts([]data.LayerPart{part}); err != nil {
+			return err
+		}
+		if err := yield(partNr, c.Inset(part, offset, insetCount, opts...)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c clipperClipper) Inset(part data.LayerPart, offset data.Micrometer, insetCount int, opts ...Option) [][]data.LayerPart {
+	o := newOptions(opts...)
+
 	var insets [][]data.LayerPart
 
-	co := clipper.NewClipperOffset()
+	// the rings which got too thin for their walls, nothing inside of them is inset anymore
+	var thinRings []data.LayerPart
+
+	// the paths which are offset, which are the previous walls when using the incremental inset
+	// Most parts have no holes, so they are only converted if there are any.
+	holes := part.Holes()
+	source := make(clipper.Paths, 0, 1+len(holes))
+	source = append(source, clipperPath(part.Outline()))
+	if len(holes) > 0 {
+		source = append(source, clipperPaths(holes)...)
+	}
 
 	for insetNr := 0; insetNr < insetCount; insetNr++ {
+		// The expansion moves all walls by the same amount, so the distance between the walls stays the same.
+		distance := float64(o.expansion) + float64(-int(offset)*insetNr) - float64(offset/2)
+		if o.incrementalInset && insetNr > 0 {
+			distance = float64(-offset)
+		}
+
 		// insets for the outline
-		co.Clear()
-		co.AddPaths(clipperPaths(data.Paths{part.Outline()}), clipper.JtSquare, clipper.EtClosedPolygon)
-		co.AddPaths(clipperPaths(part.Holes()), clipper.JtSquare, clipper.EtClosedPolygon)
+		insetParts, thin := splitThinRings(offsetToParts(source, distance, o.wallJoin, o), offset, thinRings)
+		thinRings = append(thinRings, thin...)
+		if o.incrementalInset {
+			source = partsToClipperPaths(insetParts)
+		}
 
-		co.MiterLimit = 2
-		allNewInsets := co.Execute2(float64(-int(offset)*insetNr) - float64(offset/2))
-		insets = append(insets, polyTreeToLayerParts(allNewInsets))
+		// The thin rings are not used as source for the next wall, so only their deeper walls are suppressed.
+		// If nothing of a ring is printed yet, it must not get lost:
+		// it is printed as its center line if enabled, otherwise its outer walls are kept even if they overlap.
+		for _, ring := range thin {
+			if o.thinRingCenterLine {
+				if loop := ringCenterLine(ring, offset); len(loop) >= 3 {
+					insetParts = append(insetParts, data.NewBasicLayerPart(loop, nil))
+					continue
+				}
+			}
+
+			if insetNr == 0 {
+				insetParts = append(insetParts, ring)
+			}
+		}
+
+		role := InnerWall
+		if insetNr == 0 {
+			role = OuterWall
+		}
+
+		// the insets are printed by the same extruder as the part itself
+		for i, insetPart := range insetParts {
+			insetPart.SetExtruder(part.Extruder())
+			insetParts[i] = withAttribute(insetPart, roleAttribute, role)
+		}
+		insets = append(insets, insetParts)
 	}
 
 	return insets
 }
 
-func (c clipperClipper) Difference(parts []data.LayerPart, toRemove []data.LayerPart) (clippedParts []data.LayerPart, ok bool) {
-	return c.runClipper(clipper.CtDifference, parts, toRemove)
+func (c clipperClipper) Difference(parts []data.LayerPart, toRemove []data.LayerPart, opts ...Option) (clippedParts []data.LayerPart, ok bool) {
+	return c.runClipper(clipper.CtDifference, parts, toRemove, opts...)
 }
 
-func (c clipperClipper) Intersection(parts []data.LayerPart, toIntersect []data.LayerPart) (clippedParts []data.LayerPart, ok bool) {
-	return c.runClipper(clipper.CtIntersection, parts, toIntersect)
+func (c clipperClipper) Intersection(parts []data.LayerPart, toIntersect []data.LayerPart, opts ...Option) (clippedParts []data.LayerPart, ok bool) {
+	return c.runClipper(clipper.CtIntersection, parts, toIntersect, opts...)
 }
 
-func (c clipperClipper) Union(parts []data.LayerPart, toMerge []data.LayerPart) (clippedParts []data.LayerPart, ok bool) {
-	return c.runClipper(clipper.CtUnion, parts, toMerge)
+func (c clipperClipper) Union(parts []data.LayerPart, toMerge []data.LayerPart, opts ...Option) (clippedParts []data.LayerPart, ok bool) {
+	return c.runClipper(clipper.CtUnion, parts, toMerge, opts...)
 }
 
-func (c clipperClipper) runClipper(clipType clipper.ClipType, parts []data.LayerPart, toClip []data.LayerPart) (clippedParts []data.LayerPart, ok bool) {
+func (c clipperClipper) runClipper(clipType clipper.ClipType, parts []data.LayerPart, toClip []data.LayerPart, opts ...Option) (clippedParts []data.LayerPart, ok bool) {
+	o := newOptions(opts...)
+
+	if validateParts(parts) != nil || validateParts(toClip) != nil {
+		return nil, false
+	}
+
 	cl := clipper.NewClipper(clipper.IoNone)
 	for _, part := range parts {
 		cl.AddPath(clipperPath(part.Outline()), clipper.PtSubject, true)
@@ -241,7 +622,7 @@ func (c clipperClipper) runClipper(clipType clipper.ClipType, parts []data.Layer
 		cl.AddPaths(clipperPaths(intersect.Holes()), clipper.PtClip, true)
 	}
 
-	tree, ok := cl.Execute2(clipType, clipper.PftEvenOdd, clipper.PftEvenOdd)
+	tree, ok := cl.Execute2(clipType, o.fillType.clipperFillType(), o.fillType.clipperFillType())
 
 	if !ok {
 		return nil, ok
@@ -250,6 +631,10 @@ func (c clipperClipper) runClipper(clipType clipper.ClipType, parts []data.Layer
 }
 
 func (c clipperClipper) IsCrossingPerimeter(parts []data.LayerPart, line data.Path) (result, ok bool) {
+	if validateParts(parts) != nil || validateCoordinates(line) != nil {
+		return false, false
+	}
+
 	// TODO: iIs there a more performant way to detect this?
 	cl := clipper.NewClipper(clipper.IoNone)
 
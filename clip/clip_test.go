@@ -0,0 +1,1558 @@
+package clip_test
+
+import (
+	"GoSlice/clip"
+	"GoSlice/data"
+	"GoSlice/util/test"
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// layer is a simple implementation of data.Layer used as input for the tests.
+type layer struct {
+	polygons data.Paths
+}
+
+func (l layer) Polygons() data.Paths {
+	return l.polygons
+}
+
+// rectangle returns a closed counter clockwise rectangle path.
+func rectangle(minX, minY, maxX, maxY data.Micrometer) data.Path {
+	return data.Path{
+		data.NewMicroPoint(minX, minY),
+		data.NewMicroPoint(maxX, minY),
+		data.NewMicroPoint(maxX, maxY),
+		data.NewMicroPoint(minX, maxY),
+	}
+}
+
+// assertSameOutlines fails if the outlines of the given parts are not exactly the same (including the order).
+func assertSameOutlines(t testing.TB, exp, act []data.LayerPart) {
+	test.Assert(t, len(exp) == len(act), "expected %v parts but got %v", len(exp), len(act))
+	for i := range exp {
+		expOutline, actOutline := exp[i].Outline(), act[i].Outline()
+		test.Assert(t, len(expOutline) == len(actOutline), "part %v: expected %v points but got %v", i, len(expOutline), len(actOutline))
+		for j := range expOutline {
+			test.Assert(t, expOutline[j].X() == actOutline[j].X() && expOutline[j].Y() == actOutline[j].Y(),
+				"part %v, point %v: expected (%v|%v) but got (%v|%v)", i, j,
+				expOutline[j].X(), expOutline[j].Y(), actOutline[j].X(), actOutline[j].Y())
+		}
+	}
+}
+
+func TestGenerateLayerPartsOrder(t *testing.T) {
+	polygons := data.Paths{
+		rectangle(5000, 0, 6000, 1000),
+		rectangle(0, 3000, 1000, 4000),
+		rectangle(0, 0, 1000, 1000),
+		rectangle(2000, 2000, 4000, 4000),
+		rectangle(0, 6000, 3000, 9000),
+	}
+
+	c := clip.NewClipper()
+	expected, err := c.GenerateLayerParts(context.Background(), layer{polygons: polygons})
+	test.Ok(t, err)
+	test.Assert(t, len(expected.LayerParts()) == len(polygons), "expected %v parts but got %v", len(polygons), len(expected.LayerParts()))
+
+	r := rand.New(rand.NewSource(42))
+	for i := 0; i < 20; i++ {
+		shuffled := make(data.Paths, len(polygons))
+		copy(shuffled, polygons)
+		r.Shuffle(len(shuffled), func(i, j int) {
+			shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+		})
+
+		actual, err := c.GenerateLayerParts(context.Background(), layer{polygons: shuffled})
+		test.Ok(t, err)
+		assertSameOutlines(t, expected.LayerParts(), actual.LayerParts())
+	}
+}
+
+func TestCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c := clip.NewClipper()
+	parts := []data.LayerPart{data.NewBasicLayerPart(rectangle(0, 0, 10000, 10000), nil)}
+
+	_, err := c.GenerateLayerParts(ctx, layer{polygons: data.Paths{rectangle(0, 0, 10000, 10000)}})
+	test.Assert(t, errors.Is(err, context.Canceled), "expected context.Canceled but got %v", err)
+
+	_, err = c.InsetLayer(ctx, parts, 400, 2)
+	test.Assert(t, errors.Is(err, context.Canceled), "expected context.Canceled but got %v", err)
+
+	pattern := clip.NewLinearPattern(400, 400, data.NewMicroPoint(0, 0), data.NewMicroPoint(10000, 10000), 45)
+	_, err = pattern.Fill(ctx, 0, parts[0])
+	test.Assert(t, errors.Is(err, context.Canceled), "expected context.Canceled but got %v", err)
+}
+
+func TestValidateOverlap(t *testing.T) {
+	var testCases = []struct {
+		overlap   data.Micrometer
+		lineWidth data.Micrometer
+		valid     bool
+	}{
+		{overlap: 0, lineWidth: 400, valid: true},
+		{overlap: 150, lineWidth: 400, valid: true},
+		{overlap: 400, lineWidth: 400, valid: true},
+		{overlap: 401, lineWidth: 400, valid: false},
+		{overlap: -400, lineWidth: 400, valid: true},
+		{overlap: -401, lineWidth: 400, valid: false},
+	}
+
+	for i, testCase := range testCases {
+		t.Log("testCase", i)
+		err := clip.ValidateOverlap(testCase.overlap, testCase.lineWidth)
+		test.Equals(t, testCase.valid, err == nil)
+	}
+
+	test.Equals(t, data.Micrometer(200), clip.OverlapFromPercent(400, 50))
+}
+
+func TestCubicPattern(t *testing.T) {
+	part := data.NewBasicLayerPart(rectangle(0, 0, 20000, 20000), nil)
+	pattern := clip.NewCubicPattern(400, 2000, 0, 200, 200)
+
+	var previous data.Paths
+	for layerNr := 0; layerNr < 3; layerNr++ {
+		paths, err := pattern.Fill(context.Background(), layerNr, part)
+		test.Ok(t, err)
+		test.Assert(t, len(paths) > 0, "layer %v should contain infill", layerNr)
+
+		// all lines have to stay inside of the part (allow small rounding errors by the rotation)
+		min, max := paths.Bounds()
+		test.Assert(t, min.X() >= -1 && min.Y() >= -1 && max.X() <= 20001 && max.Y() <= 20001, "the infill of layer %v exceeds the part", layerNr)
+
+		// the pattern has to move with the z height
+		if previous != nil {
+			test.Assert(t, previous[0][0].X() != paths[0][0].X() || previous[0][0].Y() != paths[0][0].Y(), "the infill of layer %v should differ from the previous layer", layerNr)
+		}
+		previous = paths
+	}
+}
+
+func TestLightning(t *testing.T) {
+	infill := []data.LayerPart{data.NewBasicLayerPart(rectangle(0, 0, 20000, 20000), nil)}
+	top := []data.LayerPart{data.NewBasicLayerPart(rectangle(5000, 5000, 10000, 10000), nil)}
+
+	lightning := clip.NewLightning(400, 400, data.NewMicroPoint(0, 0), data.NewMicroPoint(20000, 20000), 0, 1000)
+
+	// directly below the top area the infill covers the whole top area
+	paths, err := lightning.Fill(context.Background(), 3, infill, top)
+	test.Ok(t, err)
+	test.Assert(t, len(paths) > 0, "the area below the top surface should be filled")
+	min, max := paths.Bounds()
+	test.Assert(t, max.X()-min.X() > 4000, "the infill should cover the whole top area")
+
+	// for each layer below it gets smaller
+	paths, err = lightning.Fill(context.Background(), 2, infill, nil)
+	test.Ok(t, err)
+	test.Assert(t, len(paths) > 0, "the area should still be filled")
+	min, max = paths.Bounds()
+	test.Assert(t, max.X()-min.X() < 4000, "the infill should get smaller")
+
+	// until it vanishes
+	paths, err = lightning.Fill(context.Background(), 1, infill, nil)
+	test.Ok(t, err)
+	paths, err = lightning.Fill(context.Background(), 0, infill, nil)
+	test.Ok(t, err)
+	test.Equals(t, 0, len(paths))
+}
+
+func TestGenerateLayerPartsCleanup(t *testing.T) {
+	// a self-intersecting pentagram drawn by one path
+	var star data.Path
+	for i := 0; i < 5; i++ {
+		angle := float64(i*144) * math.Pi / 180
+		star = append(star, data.NewMicroPoint(data.Micrometer(10000*math.Cos(angle)), data.Micrometer(10000*math.Sin(angle))))
+	}
+
+	c := clip.NewClipper()
+
+	// by default the even-odd rule leaves the center of the star empty
+	result, err := c.GenerateLayerParts(context.Background(), layer{polygons: data.Paths{star}})
+	test.Ok(t, err)
+	parts := result.LayerParts()
+	test.Assert(t, !(len(parts) == 1 && len(parts[0].Holes()) == 0), "the center of the star should not be filled")
+
+	// using the non-zero rule for the cleanup fills the whole star
+	result, err = c.GenerateLayerParts(context.Background(), layer{polygons: data.Paths{star}}, clip.WithCleanup(clip.NonZero))
+	test.Ok(t, err)
+	parts = result.LayerParts()
+	test.Equals(t, 1, len(parts))
+	test.Equals(t, 0, len(parts[0].Holes()))
+}
+
+func TestGenerateLayerPartsFillType(t *testing.T) {
+	// two overlapping, correctly wound squares
+	polygons := data.Paths{
+		rectangle(0, 0, 2000, 2000),
+		rectangle(1000, 1000, 3000, 3000),
+	}
+
+	c := clip.NewClipper()
+
+	// even-odd leaves the overlapping area empty
+	result, err := c.GenerateLayerParts(context.Background(), layer{polygons: polygons})
+	test.Ok(t, err)
+	test.Assert(t, len(result.LayerParts()) != 1, "the overlapping area should not be filled using even-odd")
+
+	// non-zero fills the overlapping area
+	result, err = c.GenerateLayerParts(context.Background(), layer{polygons: polygons}, clip.WithFillType(clip.NonZero))
+	test.Ok(t, err)
+	test.Equals(t, 1, len(result.LayerParts()))
+	test.Equals(t, 0, len(result.LayerParts()[0].Holes()))
+}
+
+func TestInsetExpansion(t *testing.T) {
+	part := data.NewBasicLayerPart(rectangle(0, 0, 10000, 10000), nil)
+	c := clip.NewClipper()
+
+	var testCases = []struct {
+		expansion data.Micrometer
+	}{
+		{expansion: 0},
+		{expansion: 150},
+		{expansion: -150},
+	}
+
+	for i, testCase := range testCases {
+		t.Log("testCase", i)
+		insets := c.Inset(part, 400, 2, clip.WithExpansion(testCase.expansion))
+		test.Equals(t, 2, len(insets))
+
+		outerMin, _ := insets[0][0].Outline().Bounds()
+		innerMin, _ := insets[1][0].Outline().Bounds()
+
+		// the outer wall is moved by the expansion
+		test.Equals(t, data.Micrometer(200)-testCase.expansion, outerMin.X())
+		// the inner wall keeps its distance to the outer wall
+		test.Equals(t, data.Micrometer(400), innerMin.X()-outerMin.X())
+	}
+}
+
+func TestInsetExtruder(t *testing.T) {
+	part := data.NewBasicLayerPart(rectangle(0, 0, 10000, 10000), nil)
+	part.SetExtruder(1)
+	c := clip.NewClipper()
+
+	insets, err := c.InsetLayer(context.Background(), []data.LayerPart{part}, 400, 2)
+	test.Ok(t, err)
+
+	for _, inset := range insets[0] {
+		for _, insetPart := range inset {
+			test.Equals(t, 1, insetPart.Extruder())
+			test.Assert(t, insetPart.IsClosed(), "all walls should be closed")
+		}
+	}
+}
+
+func TestInsetLayerFunc(t *testing.T) {
+	parts := []data.LayerPart{
+		data.NewBasicLayerPart(rectangle(0, 0, 10000, 10000), nil),
+		data.NewBasicLayerPart(rectangle(20000, 0, 30000, 10000), data.Paths{reversed(rectangle(24000, 4000, 26000, 6000))}),
+		data.NewBasicLayerPart(rectangle(40000, 0, 50000, 10000), nil),
+	}
+	c := clip.NewClipper()
+
+	expected, err := c.InsetLayer(context.Background(), parts, 400, 2)
+	test.Ok(t, err)
+
+	// the parts are yielded in their order with the same insets as returned by InsetLayer
+	var partNrs []int
+	err = c.InsetLayerFunc(context.Background(), parts, 400, 2, func(partNr int, insets [][]data.LayerPart) error {
+		partNrs = append(partNrs, partNr)
+		test.Equals(t, len(expected[partNr]), len(insets))
+		for insetNr, inset := range insets {
+			assertSameOutlines(t, expected[partNr][insetNr], inset)
+		}
+		return nil
+	})
+	test.Ok(t, err)
+	test.Equals(t, []int{0, 1, 2}, partNrs)
+
+	// an error of the yield function stops the inset
+	stop := errors.New("stop")
+	partNrs = nil
+	err = c.InsetLayerFunc(context.Background(), parts, 400, 2, func(partNr int, insets [][]data.LayerPart) error {
+		partNrs = append(partNrs, partNr)
+		return stop
+	})
+	test.Assert(t, errors.Is(err, stop), "expected the error of the yield function but got %v", err)
+	test.Equals(t, []int{0}, partNrs)
+}
+
+func TestConcentricPattern(t *testing.T) {
+	part := data.NewBasicLayerPart(rectangle(0, 0, 10000, 4000), nil)
+	pattern := clip.NewConcentricPattern(400, 400)
+
+	paths, err := pattern.Fill(context.Background(), 0, part)
+	test.Ok(t, err)
+
+	// 4000 / 400 = 10 lines from each side, which results in 5 loops
+	loops := 0
+	lines := 0
+	for _, path := range paths {
+		first, last := path[0], path[len(path)-1]
+		if first.X() == last.X() && first.Y() == last.Y() {
+			loops++
+		} else {
+			lines++
+		}
+	}
+	test.Equals(t, 5, loops)
+
+	// the first loop is half a line width inside of the outline
+	min, max := paths[0].Bounds()
+	test.Equals(t, data.Micrometer(200), min.X())
+	test.Equals(t, data.Micrometer(9800), max.X())
+
+	// a thin part leaves a remnant in the middle which is filled by a line
+	part = data.NewBasicLayerPart(rectangle(0, 0, 10000, 1800), nil)
+	paths, err = pattern.Fill(context.Background(), 0, part)
+	test.Ok(t, err)
+	test.Equals(t, 3, len(paths))
+	last := paths[len(paths)-1]
+	test.Equals(t, 2, len(last))
+	test.Equals(t, data.Micrometer(900), last[0].Y())
+	test.Equals(t, data.Micrometer(900), last[1].Y())
+}
+
+func TestSplitSupportInterface(t *testing.T) {
+	support := data.Paths{rectangle(0, 0, 10000, 10000)}
+	above := []data.Paths{
+		nil,
+		{rectangle(0, 0, 5000, 10000)},
+	}
+
+	var testCases = []struct {
+		interfaceLayers int
+		interfaceCount  int
+		interfaceMaxX   data.Micrometer
+		bodyMinX        data.Micrometer
+	}{
+		{interfaceLayers: 0, interfaceCount: 0, bodyMinX: 0},
+		{interfaceLayers: 1, interfaceCount: 0, bodyMinX: 0},
+		{interfaceLayers: 2, interfaceCount: 1, interfaceMaxX: 5000, bodyMinX: 5000},
+		{interfaceLayers: 5, interfaceCount: 1, interfaceMaxX: 5000, bodyMinX: 5000},
+	}
+
+	for i, testCase := range testCases {
+		t.Log("testCase", i)
+		interfaceParts, bodyParts, err := clip.SplitSupportInterface(support, above, testCase.interfaceLayers)
+		test.Ok(t, err)
+		test.Equals(t, testCase.interfaceCount, len(interfaceParts))
+		test.Equals(t, 1, len(bodyParts))
+
+		if testCase.interfaceCount > 0 {
+			_, max := interfaceParts[0].Outline().Bounds()
+			test.Equals(t, testCase.interfaceMaxX, max.X())
+		}
+
+		min, _ := bodyParts[0].Outline().Bounds()
+		test.Equals(t, testCase.bodyMinX, min.X())
+	}
+
+	// a vertical wall above the support overlaps on all interface layers
+	wall := data.Paths{rectangle(0, 0, 5000, 10000)}
+	for layers := 1; layers <= 4; layers++ {
+		t.Log("layers", layers)
+		above := make([]data.Paths, layers)
+		for i := range above {
+			above[i] = wall
+		}
+
+		interfaceParts, bodyParts, err := clip.SplitSupportInterface(support, above, layers)
+		test.Ok(t, err)
+		test.Equals(t, 1, len(interfaceParts))
+		test.Equals(t, 1, len(bodyParts))
+
+		_, max := interfaceParts[0].Outline().Bounds()
+		test.Equals(t, data.Micrometer(5000), max.X())
+		min, _ := bodyParts[0].Outline().Bounds()
+		test.Equals(t, data.Micrometer(5000), min.X())
+	}
+}
+
+func TestHoneycombPattern(t *testing.T) {
+	part := data.NewBasicLayerPart(rectangle(0, 0, 20000, 20000), nil)
+	pattern := clip.NewHoneycombPattern(400, 2000)
+
+	paths, err := pattern.Fill(context.Background(), 0, part)
+	test.Ok(t, err)
+	test.Assert(t, len(paths) > 0, "expected some infill")
+
+	// each edge is only extruded once
+	type segment struct {
+		x1, y1, x2, y2 data.Micrometer
+	}
+	seen := map[segment]bool{}
+	for _, path := range paths {
+		test.Equals(t, 2, len(path))
+		a, b := path[0], path[1]
+		if a.X() > b.X() || (a.X() == b.X() && a.Y() > b.Y()) {
+			a, b = b, a
+		}
+		s := segment{a.X(), a.Y(), b.X(), b.Y()}
+		test.Assert(t, !seen[s], "the edge %v is extruded twice", s)
+		seen[s] = true
+	}
+
+	// the honeycomb uses about the same amount of material as lines with the same distance
+	expected := 20000.0 * 20000.0 / 2000.0
+	actual := float64(paths.TotalLength(false))
+	test.Assert(t, math.Abs(actual-expected)/expected < 0.1, "expected a length of about %v but got %v", expected, actual)
+}
+
+func TestCrossPattern(t *testing.T) {
+	part := data.NewBasicLayerPart(rectangle(0, 0, 10000, 10000), nil)
+
+	var testCases = []struct {
+		skipPeriod int
+		skipPhase  int
+		maxLength  data.Micrometer
+	}{
+		{skipPeriod: 0, maxLength: 10000},
+		{skipPeriod: 2, skipPhase: 0, maxLength: 1000},
+		{skipPeriod: 2, skipPhase: 1, maxLength: 1000},
+		{skipPeriod: 3, skipPhase: 0, maxLength: 2000},
+	}
+
+	for i, testCase := range testCases {
+		t.Log("testCase", i)
+		pattern := clip.NewCrossPattern(400, 1000, 0, testCase.skipPeriod, testCase.skipPhase)
+		paths, err := pattern.Fill(context.Background(), 0, part)
+		test.Ok(t, err)
+		test.Assert(t, len(paths) > 0, "expected some infill")
+
+		longest := data.Micrometer(0)
+		for _, path := range paths {
+			if length := path.Length(false); length > longest {
+				longest = length
+			}
+
+			// the segments are aligned to the grid
+			for _, point := range path {
+				test.Assert(t, point.X()%1000 == 0 || point.Y()%1000 == 0, "the point (%v|%v) is not on the grid", point.X(), point.Y())
+			}
+		}
+		test.Equals(t, testCase.maxLength, longest)
+	}
+}
+
+func TestThinWalls(t *testing.T) {
+	// a square with a thin tab on the right side
+	part := data.NewBasicLayerPart(data.Path{
+		data.NewMicroPoint(0, 0),
+		data.NewMicroPoint(10000, 0),
+		data.NewMicroPoint(10000, 4700),
+		data.NewMicroPoint(20000, 4700),
+		data.NewMicroPoint(20000, 5300),
+		data.NewMicroPoint(10000, 5300),
+		data.NewMicroPoint(10000, 10000),
+		data.NewMicroPoint(0, 10000),
+	}, nil)
+
+	part.SetExtruder(1)
+
+	thick, centerLines, err := clip.ThinWalls(part, 400, 800)
+	test.Ok(t, err)
+
+	test.Equals(t, 1, len(thick))
+	min, max := thick[0].Outline().Bounds()
+	test.Equals(t, []data.Micrometer{0, 0, 10000, 10000}, []data.Micrometer{min.X(), min.Y(), max.X(), max.Y()})
+	test.Equals(t, 1, thick[0].Extruder())
+
+	test.Equals(t, 1, len(centerLines))
+	min, max = centerLines[0].Bounds()
+	test.Equals(t, data.Micrometer(5000), min.Y())
+	test.Equals(t, data.Micrometer(5000), max.Y())
+	test.Assert(t, min.X() >= 10000 && min.X() <= 10400, "the center line should start at the tab but starts at %v", min.X())
+	test.Assert(t, max.X() <= 20000 && max.X() >= 19600, "the center line should end at the tab end but ends at %v", max.X())
+
+	// disabled
+	thick, centerLines, err = clip.ThinWalls(part, 400, 0)
+	test.Ok(t, err)
+	test.Equals(t, 1, len(thick))
+	test.Equals(t, 0, len(centerLines))
+}
+
+func TestThinWallsDirections(t *testing.T) {
+	// the legs of an L run along both scan directions
+	l := data.NewBasicLayerPart(data.Path{
+		data.NewMicroPoint(0, 0),
+		data.NewMicroPoint(10000, 0),
+		data.NewMicroPoint(10000, 600),
+		data.NewMicroPoint(600, 600),
+		data.NewMicroPoint(600, 10000),
+		data.NewMicroPoint(0, 10000),
+	}, nil)
+
+	thick, centerLines, err := clip.ThinWalls(l, 400, 800)
+	test.Ok(t, err)
+	test.Equals(t, 0, len(thick))
+
+	var horizontal, vertical bool
+	for _, line := range centerLines {
+		min, max := line.Bounds()
+		horizontal = horizontal || min.Y() == 300 && max.Y() == 300 && max.X()-min.X() >= 8000
+		vertical = vertical || min.X() == 300 && max.X() == 300 && max.Y()-min.Y() >= 8000
+	}
+	test.Assert(t, horizontal, "expected a center line along the horizontal leg, got %v", centerLines)
+	test.Assert(t, vertical, "expected a center line along the vertical leg, got %v", centerLines)
+}
+
+func TestThinWallsTube(t *testing.T) {
+	// a square tube results in one loop through all of its sides
+	square := data.NewBasicLayerPart(rectangle(0, 0, 10000, 10000), data.Paths{reversed(rectangle(600, 600, 9400, 9400))})
+	thick, centerLines, err := clip.ThinWalls(square, 400, 800)
+	test.Ok(t, err)
+	test.Equals(t, 0, len(thick))
+	test.Equals(t, 1, len(centerLines))
+	min, max := centerLines[0].Bounds()
+	test.Equals(t, []data.Micrometer{300, 300, 9700, 9700}, []data.Micrometer{min.X(), min.Y(), max.X(), max.Y()})
+	test.Assert(t, centerLines[0].IsAlmostFinished(0), "the loop should be closed")
+
+	// all points of the loop of a round tube are in the middle of the wall
+	var outline, hole data.Path
+	for i := 0; i < 180; i++ {
+		angle := 2 * math.Pi * float64(i) / 180
+		outline = append(outline, data.NewMicroPoint(data.Micrometer(5000*math.Cos(angle)), data.Micrometer(5000*math.Sin(angle))))
+		hole = append(hole, data.NewMicroPoint(data.Micrometer(4400*math.Cos(angle)), data.Micrometer(4400*math.Sin(angle))))
+	}
+	round := data.NewBasicLayerPart(outline, data.Paths{reversed(hole)})
+
+	thick, centerLines, err = clip.ThinWalls(round, 400, 800)
+	test.Ok(t, err)
+	test.Equals(t, 0, len(thick))
+	test.Equals(t, 1, len(centerLines))
+	for _, point := range centerLines[0] {
+		radius := math.Hypot(float64(point.X()), float64(point.Y()))
+		test.Assert(t, radius > 4650 && radius < 4750, "the point (%v|%v) is not in the middle of the wall", point.X(), point.Y())
+	}
+	min, max = centerLines[0].Bounds()
+	test.Assert(t, min.X() < -4650 && max.X() > 4650, "the loop should go around the whole tube")
+}
+
+func TestThinWallsCircle(t *testing.T) {
+	// circles have no thin regions, the slivers of rounding errors along the outline are ignored
+	for _, radius := range []float64{10000, 20000} {
+		t.Log("radius", radius)
+		var circle data.Path
+		for i := 0; i < 360; i++ {
+			angle := 2 * math.Pi * float64(i) / 360
+			circle = append(circle, data.NewMicroPoint(data.Micrometer(radius*math.Cos(angle)), data.Micrometer(radius*math.Sin(angle))))
+		}
+
+		thick, centerLines, err := clip.ThinWalls(data.NewBasicLayerPart(circle, nil), 400, 800)
+		test.Ok(t, err)
+		test.Equals(t, 1, len(thick))
+		test.Equals(t, 0, len(centerLines))
+	}
+}
+
+func TestWalkInsets(t *testing.T) {
+	outer := data.NewBasicLayerPart(rectangle(0, 0, 100, 100), data.Paths{rectangle(40, 40, 60, 60), {}})
+	inner := data.NewBasicLayerPart(rectangle(10, 10, 90, 90), nil)
+	empty := data.NewBasicLayerPart(data.Path{}, nil)
+
+	insets := [][][]data.LayerPart{
+		{{outer}, {inner, empty}},
+		{{outer}},
+	}
+
+	type visited struct {
+		PartNr, InsetNr, HoleCount int
+		Role                       clip.WallRole
+	}
+
+	var testCases = []struct {
+		outerLast bool
+		expected  []visited
+	}{
+		{outerLast: false, expected: []visited{{0, 0, 1, clip.OuterWall}, {0, 1, 0, clip.InnerWall}, {1, 0, 1, clip.OuterWall}}},
+		{outerLast: true, expected: []visited{{0, 1, 0, clip.InnerWall}, {0, 0, 1, clip.OuterWall}, {1, 0, 1, clip.OuterWall}}},
+	}
+
+	for i, testCase := range testCases {
+		t.Log("testCase", i)
+		var result []visited
+		err := clip.WalkInsets(insets, testCase.outerLast, func(partNr int, insetNr int, role clip.WallRole, outline data.Path, holes data.Paths, closed bool) error {
+			result = append(result, visited{partNr, insetNr, len(holes), role})
+			return nil
+		})
+		test.Ok(t, err)
+		test.Equals(t, testCase.expected, result)
+	}
+
+	// an error stops the walk
+	expectedErr := errors.New("stop")
+	count := 0
+	err := clip.WalkInsets(insets, false, func(partNr int, insetNr int, role clip.WallRole, outline data.Path, holes data.Paths, closed bool) error {
+		count++
+		return expectedErr
+	})
+	test.Assert(t, errors.Is(err, expectedErr), "expected the error of the visitor")
+	test.Equals(t, 1, count)
+}
+
+func TestLinearPatternSmallPart(t *testing.T) {
+	// a small part on a big plate
+	part := data.NewBasicLayerPart(rectangle(50150, 50150, 52150, 51150), nil)
+	pattern := clip.NewLinearPattern(400, 1000, data.NewMicroPoint(0, 0), data.NewMicroPoint(100000, 100000), 0)
+
+	// layer 1 is not rotated
+	paths, err := pattern.Fill(context.Background(), 1, part)
+	test.Ok(t, err)
+
+	// the lines stay on the grid of the whole plate
+	test.Equals(t, 2, len(paths))
+	for _, path := range paths {
+		test.Equals(t, 2, len(path))
+		test.Equals(t, data.Micrometer(0), path[0].X()%1000)
+		test.Equals(t, path[0].X(), path[1].X())
+	}
+}
+
+func TestInsetWithOrigin(t *testing.T) {
+	// the small hole near the corner merges with the outline after the first inset, the big one stays
+	part := data.NewBasicLayerPart(rectangle(0, 0, 10000, 10000), data.Paths{
+		reversed(rectangle(1000, 1000, 1400, 1400)),
+		reversed(rectangle(3000, 3000, 7000, 7000)),
+	})
+	c := clip.NewClipper()
+
+	insets := c.InsetWithOrigin(part, 400, 3)
+	test.Equals(t, 3, len(insets))
+
+	var origins [][]clip.Origin
+	for _, inset := range insets {
+		var insetOrigins []clip.Origin
+		for _, group := range inset {
+			test.Equals(t, 1, len(group.Paths))
+			insetOrigins = append(insetOrigins, group.Origin)
+		}
+		origins = append(origins, insetOrigins)
+	}
+
+	test.Equals(t, [][]clip.Origin{
+		{clip.OutlineOrigin, 0, 1},
+		{clip.OutlineOrigin, 1},
+		{clip.OutlineOrigin, 1},
+	}, origins)
+	test.Assert(t, !clip.OutlineOrigin.IsHole(), "the outline is no hole")
+}
+
+// reversed returns a reversed copy of the path.
+func reversed(path data.Path) data.Path {
+	result := make(data.Path, len(path))
+	for i, point := range path {
+		result[len(path)-1-i] = point
+	}
+	return result
+}
+
+func TestInsetArcTolerance(t *testing.T) {
+	part := data.NewBasicLayerPart(rectangle(0, 0, 10000, 10000), nil)
+	c := clip.NewClipper()
+
+	// square joins are not affected by the arc tolerance
+	expected := c.Inset(part, 400, 2)
+	actual := c.Inset(part, 400, 2, clip.WithArcTolerance(50))
+	test.Equals(t, len(expected), len(actual))
+	for i := range expected {
+		assertSameOutlines(t, expected[i], actual[i])
+	}
+
+	// A lower tolerance results in more points on the rounded corners.
+	// The corners are only rounded if the offset grows the outline, so the part is ex-set.
+	coarse := c.Inset(part, -2000, 1, clip.WithWallJoin(clip.RoundJoin), clip.WithArcTolerance(200))
+	fine := c.Inset(part, -2000, 1, clip.WithWallJoin(clip.RoundJoin), clip.WithArcTolerance(5))
+	test.Equals(t, 1, len(coarse[0]))
+	test.Equals(t, 1, len(fine[0]))
+	test.Assert(t, len(fine[0][0].Outline()) > len(coarse[0][0].Outline()),
+		"expected more points with the lower tolerance, got %v and %v", len(fine[0][0].Outline()), len(coarse[0][0].Outline()))
+}
+
+func TestMedialAxis(t *testing.T) {
+	part := data.NewBasicLayerPart(rectangle(0, 0, 10000, 4000), nil)
+
+	axis, err := clip.MedialAxis(part, 200)
+	test.Ok(t, err)
+	test.Assert(t, len(axis) > 0, "expected a medial axis")
+
+	// the axis of a rectangle runs along its center
+	for _, line := range axis {
+		for _, point := range line {
+			test.Assert(t, point.Y() >= 1800 && point.Y() <= 2200, "the point (%v|%v) is not near the center", point.X(), point.Y())
+		}
+	}
+
+	min, max := axis.Bounds()
+	test.Assert(t, max.X()-min.X() >= 5000, "the axis should run along the longer side but spans only %v", max.X()-min.X())
+
+	axis, err = clip.MedialAxis(part, 0)
+	test.Ok(t, err)
+	test.Equals(t, 0, len(axis))
+
+	// the axis of a ring is a loop through the middle of all of its sides
+	ring := data.NewBasicLayerPart(rectangle(0, 0, 10000, 10000), data.Paths{reversed(rectangle(2000, 2000, 8000, 8000))})
+	axis, err = clip.MedialAxis(ring, 200)
+	test.Ok(t, err)
+	test.Assert(t, len(axis) > 0, "expected a medial axis")
+
+	min, max = axis.Bounds()
+	test.Equals(t, []data.Micrometer{1000, 1000, 9000, 9000}, []data.Micrometer{min.X(), min.Y(), max.X(), max.Y()})
+	for _, line := range axis {
+		for _, point := range line {
+			onSide := point.X() == 1000 || point.X() == 9000 || point.Y() == 1000 || point.Y() == 9000
+			test.Assert(t, onSide, "the point (%v|%v) is not in the middle of the band", point.X(), point.Y())
+		}
+	}
+}
+
+func TestIncrementalInset(t *testing.T) {
+	c := clip.NewClipper()
+
+	// for a rectangle both ways give the same walls
+	part := data.NewBasicLayerPart(rectangle(0, 0, 10000, 10000), nil)
+	expected := c.Inset(part, 400, 3)
+	actual := c.Inset(part, 400, 3, clip.WithIncrementalInset())
+	test.Equals(t, len(expected), len(actual))
+	for i := range expected {
+		assertSameOutlines(t, expected[i], actual[i])
+	}
+
+	// after a wall collapsed, no further walls are generated
+	small := data.NewBasicLayerPart(rectangle(0, 0, 1400, 1400), nil)
+	insets := c.Inset(small, 400, 3, clip.WithIncrementalInset())
+	test.Equals(t, 3, len(insets))
+	test.Equals(t, 1, len(insets[0]))
+	test.Equals(t, 1, len(insets[1]))
+	test.Equals(t, 0, len(insets[2]))
+}
+
+func TestLinearPatternWallOverlap(t *testing.T) {
+	part := data.NewBasicLayerPart(rectangle(0, 0, 10000, 10000), nil)
+	// only the bottom edge of the part touches a wall
+	walls := []data.LayerPart{data.NewBasicLayerPart(rectangle(0, -1000, 10000, 0), nil)}
+
+	pattern, err := clip.NewLinearPatternWithWallOverlap(400, 1000, data.NewMicroPoint(500, 500), data.NewMicroPoint(100000, 100000), 0, -100, 200)
+	test.Ok(t, err)
+
+	// layer 1 is not rotated, so the lines are vertical
+	paths, err := pattern.FillNextToWalls(context.Background(), 1, part, walls)
+	test.Ok(t, err)
+
+	test.Equals(t, 10, len(paths))
+	for _, path := range paths {
+		test.Equals(t, 2, len(path))
+		min, max := path.Bounds()
+		test.Equals(t, data.Micrometer(-200), min.Y())
+		test.Equals(t, data.Micrometer(9900), max.Y())
+	}
+
+	// without walls the normal overlap is used everywhere
+	paths, err = pattern.Fill(context.Background(), 1, part)
+	test.Ok(t, err)
+
+	test.Equals(t, 10, len(paths))
+	for _, path := range paths {
+		min, max := path.Bounds()
+		test.Equals(t, data.Micrometer(100), min.Y())
+		test.Equals(t, data.Micrometer(9900), max.Y())
+	}
+
+	_, err = clip.NewLinearPatternWithWallOverlap(400, 1000, data.NewMicroPoint(0, 0), data.NewMicroPoint(100000, 100000), 0, 0, 500)
+	test.Assert(t, err != nil, "a wall overlap larger than the line width should fail")
+}
+
+func TestGroundedParts(t *testing.T) {
+	base := data.NewBasicLayerPart(rectangle(0, 0, 10000, 10000), nil)
+	island := data.NewBasicLayerPart(rectangle(20000, 0, 30000, 10000), nil)
+	// overlaps the base only by 100 x 100
+	corner := data.NewBasicLayerPart(rectangle(9900, 9900, 15000, 15000), nil)
+
+	layers := [][]data.LayerPart{
+		{base},
+		{base, island},
+		{corner, island, base},
+	}
+
+	grounded, err := clip.GroundedParts(layers, 20000)
+	test.Ok(t, err)
+	test.Equals(t, [][]bool{
+		{true},
+		{true, false},
+		{false, false, true},
+	}, grounded)
+
+	// with a smaller threshold the corner is grounded
+	grounded, err = clip.GroundedParts(layers, 10000)
+	test.Ok(t, err)
+	test.Equals(t, []bool{true, false, true}, grounded[2])
+}
+
+// fixedPattern is a clip.Pattern which always returns the same paths.
+type fixedPattern struct {
+	paths data.Paths
+}
+
+func (p fixedPattern) Fill(ctx context.Context, layerNr int, part data.LayerPart) (data.Paths, error) {
+	return p.paths, nil
+}
+
+func TestChainedPattern(t *testing.T) {
+	pattern := clip.NewChainedPattern(fixedPattern{paths: data.Paths{
+		{data.NewMicroPoint(0, 0), data.NewMicroPoint(1000, 0)},
+		{data.NewMicroPoint(5000, 0), data.NewMicroPoint(10000, 0)},
+		{data.NewMicroPoint(2000, 0), data.NewMicroPoint(1500, 0)},
+	}})
+
+	paths, err := pattern.Fill(context.Background(), 0, data.NewBasicLayerPart(rectangle(0, 0, 10000, 10000), nil))
+	test.Ok(t, err)
+
+	// the last line is nearer and is reversed, as its end is nearer than its start
+	var xs [][]data.Micrometer
+	for _, path := range paths {
+		var x []data.Micrometer
+		for _, point := range path {
+			x = append(x, point.X())
+		}
+		xs = append(xs, x)
+	}
+	test.Equals(t, [][]data.Micrometer{{0, 1000}, {1500, 2000}, {5000, 10000}}, xs)
+
+	// the chained output contains the connectors between the lines flagged as travel
+	chain, err := pattern.FillChained(context.Background(), 0, data.NewBasicLayerPart(rectangle(0, 0, 10000, 10000), nil))
+	test.Ok(t, err)
+
+	xs = nil
+	for _, path := range chain.Paths {
+		var x []data.Micrometer
+		for _, point := range path {
+			x = append(x, point.X())
+		}
+		xs = append(xs, x)
+	}
+	test.Equals(t, [][]data.Micrometer{{0, 1000}, {1000, 1500}, {1500, 2000}, {2000, 5000}, {5000, 10000}}, xs)
+	test.Equals(t, []bool{false, true, false, true, false}, chain.Travel)
+	test.Equals(t, len(paths), len(chain.Lines()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = pattern.Fill(ctx, 0, data.NewBasicLayerPart(rectangle(0, 0, 10000, 10000), nil))
+	test.Assert(t, errors.Is(err, context.Canceled), "expected the context error but got %v", err)
+	_, err = pattern.FillChained(ctx, 0, data.NewBasicLayerPart(rectangle(0, 0, 10000, 10000), nil))
+	test.Assert(t, errors.Is(err, context.Canceled), "expected the context error but got %v", err)
+}
+
+func TestGenerateLayerPartsCoordinateRange(t *testing.T) {
+	c := clip.NewClipper()
+
+	// the largest allowed coordinates work
+	_, err := c.GenerateLayerParts(context.Background(), layer{polygons: data.Paths{rectangle(-clip.MaxCoordinate, -clip.MaxCoordinate, clip.MaxCoordinate, clip.MaxCoordinate)}})
+	test.Ok(t, err)
+
+	_, err = c.GenerateLayerParts(context.Background(), layer{polygons: data.Paths{rectangle(0, 0, clip.MaxCoordinate+1, 1000)}})
+	var coordinateErr *clip.CoordinateError
+	test.Assert(t, errors.As(err, &coordinateErr), "expected a CoordinateError but got %v", err)
+	test.Equals(t, clip.MaxCoordinate+1, coordinateErr.Point.X())
+}
+
+func TestCoordinateRange(t *testing.T) {
+	c := clip.NewClipper()
+	tooLarge := rectangle(0, 0, clip.MaxCoordinate+1, 1000)
+	part := data.NewBasicLayerPart(tooLarge, nil)
+	parts := []data.LayerPart{part}
+	ctx := context.Background()
+
+	var testCases = []func() error{
+		func() error {
+			_, err := c.InsetLayer(ctx, parts, 400, 2)
+			return err
+		},
+		func() error {
+			_, err := c.Offset(data.Paths{tooLarge}, 400, clip.SquareJoin)
+			return err
+		},
+		func() error {
+			_, err := clip.NewLinearPattern(400, 400, data.NewMicroPoint(0, 0), data.NewMicroPoint(10000, 10000), 0).Fill(ctx, 0, part)
+			return err
+		},
+		func() error {
+			_, err := clip.NewConcentricPattern(400, 400).Fill(ctx, 0, part)
+			return err
+		},
+		func() error {
+			_, err := clip.NewHoneycombPattern(400, 2000).Fill(ctx, 0, part)
+			return err
+		},
+		func() error {
+			_, err := clip.FillParts(ctx, clip.NewMultiLinePattern(400, 1000, []int{0, 60, 120}), 0, parts)
+			return err
+		},
+		func() error {
+			_, err := clip.FillMasked(ctx, clip.NewConcentricPattern(400, 400), 0, data.NewBasicLayerPart(rectangle(0, 0, 1000, 1000), nil), data.Paths{tooLarge})
+			return err
+		},
+		func() error {
+			_, err := clip.CompensateElephantFoot(data.NewPartitionedLayer(parts), 200)
+			return err
+		},
+		func() error {
+			_, err := clip.GroundedParts([][]data.LayerPart{parts}, 0)
+			return err
+		},
+		func() error {
+			_, _, err := clip.SplitSupportInterface(data.Paths{tooLarge}, nil, 1)
+			return err
+		},
+		func() error {
+			_, _, err := clip.ThinWalls(part, 400, 800)
+			return err
+		},
+		func() error {
+			_, err := clip.NewPartTracker().Track(parts)
+			return err
+		},
+	}
+
+	for i, testCase := range testCases {
+		t.Log("testCase", i)
+		var coordinateErr *clip.CoordinateError
+		err := testCase()
+		test.Assert(t, errors.As(err, &coordinateErr), "expected a CoordinateError but got %v", err)
+	}
+
+	// the functions without an error report the failure by ok
+	_, ok := c.Union(parts, nil)
+	test.Assert(t, !ok, "the union of too large coordinates should fail")
+	_, ok = c.IsCrossingPerimeter(parts, data.Path{data.NewMicroPoint(0, 0), data.NewMicroPoint(10, 10)})
+	test.Assert(t, !ok, "the check of too large coordinates should fail")
+}
+
+func TestGenerateLayerPartsStats(t *testing.T) {
+	square := data.Path{
+		data.NewMicroPoint(0, 0),
+		data.NewMicroPoint(5000, 0),
+		data.NewMicroPoint(10000, 0),
+		data.NewMicroPoint(10000, 10000),
+		data.NewMicroPoint(0, 10000),
+	}
+	line := data.Path{data.NewMicroPoint(20000, 0), data.NewMicroPoint(30000, 0)}
+
+	var stats clip.LayerPartsStats
+	c := clip.NewClipper()
+	result, err := c.GenerateLayerParts(context.Background(), layer{polygons: data.Paths{square, line}}, clip.WithStats(&stats))
+	test.Ok(t, err)
+	test.Equals(t, 1, len(result.LayerParts()))
+
+	// the point on the straight edge is removed and the line is removed completely as it has no area
+	test.Equals(t, clip.LayerPartsStats{
+		InputPolygons:      2,
+		OutputParts:        1,
+		FilteredPoints:     3,
+		DegeneratePolygons: 1,
+	}, stats)
+}
+
+func TestWallWidths(t *testing.T) {
+	var tests = []struct {
+		part       data.LayerPart
+		wall       data.Path
+		insetCount int
+		expWidth   data.Micrometer
+	}{
+		// a wide part uses the normal line width
+		{
+			part:       data.NewBasicLayerPart(rectangle(0, 0, 10000, 10000), nil),
+			wall:       rectangle(200, 200, 9800, 9800),
+			insetCount: 2,
+			expWidth:   400,
+		},
+		// one wall on each side fills the whole 1000 wide part
+		{
+			part:       data.NewBasicLayerPart(rectangle(0, 0, 10000, 1000), nil),
+			wall:       rectangle(200, 200, 9800, 800),
+			insetCount: 2,
+			expWidth:   500,
+		},
+		// two walls on each side fill the whole 1500 wide part
+		{
+			part:       data.NewBasicLayerPart(rectangle(0, 0, 10000, 1500), nil),
+			wall:       rectangle(200, 200, 9800, 1300),
+			insetCount: 2,
+			expWidth:   375,
+		},
+	}
+
+	for i, testCase := range tests {
+		t.Log("testCase", i)
+
+		widths, err := clip.WallWidths(testCase.part, testCase.wall, 400, testCase.insetCount)
+		test.Ok(t, err)
+		test.Equals(t, len(testCase.wall), len(widths))
+
+		// the long segments at the bottom (0) and the top (2)
+		test.Equals(t, testCase.expWidth, widths[0])
+		test.Equals(t, testCase.expWidth, widths[2])
+	}
+}
+
+func TestFillMasked(t *testing.T) {
+	part := data.NewBasicLayerPart(rectangle(0, 0, 10000, 10000), nil)
+	pattern := clip.NewLinearPattern(400, 1000, data.NewMicroPoint(500, 500), data.NewMicroPoint(100000, 100000), 0)
+
+	// without a mask the result is the same as Fill
+	exp, err := pattern.Fill(context.Background(), 1, part)
+	test.Ok(t, err)
+	paths, err := clip.FillMasked(context.Background(), pattern, 1, part, nil)
+	test.Ok(t, err)
+	test.Equals(t, len(exp), len(paths))
+
+	// only the lines in the left half of the lower half are kept
+	paths, err = clip.FillMasked(context.Background(), pattern, 1, part, data.Paths{rectangle(-1000, -1000, 5000, 5000)})
+	test.Ok(t, err)
+	test.Equals(t, 5, len(paths))
+	for _, path := range paths {
+		min, max := path.Bounds()
+		test.Assert(t, max.X() <= 5000, "the line at %v should be inside of the mask", max.X())
+		test.Equals(t, data.Micrometer(0), min.Y())
+		test.Equals(t, data.Micrometer(5000), max.Y())
+	}
+}
+
+func BenchmarkInset(b *testing.B) {
+	// a solid part without holes, which is the most common case
+	var circle data.Path
+	for i := 0; i < 360; i++ {
+		angle := float64(i) * math.Pi / 180
+		circle = append(circle, data.NewMicroPoint(data.Micrometer(20000*math.Cos(angle)), data.Micrometer(20000*math.Sin(angle))))
+	}
+	part := data.NewBasicLayerPart(circle, nil)
+	c := clip.NewClipper()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Inset(part, 400, 3)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	var tests = []struct {
+		polygons data.Paths
+		exp      []clip.Warning
+	}{
+		{
+			polygons: data.Paths{rectangle(0, 0, 1000, 1000)},
+			exp:      nil,
+		},
+		{
+			polygons: data.Paths{
+				rectangle(0, 0, 1000, 1000),
+				{data.NewMicroPoint(0, 0), data.NewMicroPoint(1000, 0)},
+				{data.NewMicroPoint(0, 0), data.NewMicroPoint(1000, 0), data.NewMicroPoint(2000, 0)},
+				rectangle(0, 0, clip.MaxCoordinate+1, 1000),
+			},
+			exp: []clip.Warning{
+				{PolygonIndex: 1, Issue: clip.TooFewPoints},
+				{PolygonIndex: 2, Issue: clip.ZeroArea},
+				{PolygonIndex: 3, Issue: clip.CoordinateOutOfRange},
+			},
+		},
+	}
+
+	for i, testCase := range tests {
+		t.Log("testCase", i)
+		test.Equals(t, testCase.exp, clip.Validate(layer{polygons: testCase.polygons}))
+	}
+
+	test.Equals(t, "polygon 2: zero area", clip.Warning{PolygonIndex: 2, Issue: clip.ZeroArea}.String())
+}
+
+func TestFillParts(t *testing.T) {
+	parts := []data.LayerPart{
+		data.NewBasicLayerPart(rectangle(0, 0, 10000, 10000), nil),
+		data.NewBasicLayerPart(rectangle(100000, 100000, 100100, 100100), nil),
+		data.NewBasicLayerPart(rectangle(20000, 0, 25000, 10000), nil),
+	}
+	pattern := clip.NewLinearPattern(400, 1000, data.NewMicroPoint(500, 500), data.NewMicroPoint(200000, 200000), 0)
+
+	groups, err := clip.FillParts(context.Background(), pattern, 1, parts)
+	test.Ok(t, err)
+	test.Equals(t, 3, len(groups))
+
+	// the lines of each group are inside of their part
+	for i, group := range groups {
+		partMin, partMax := parts[i].Outline().Bounds()
+		for _, path := range group {
+			min, max := path.Bounds()
+			test.Assert(t, min.X() >= partMin.X() && max.X() <= partMax.X(), "group %v: the line at %v is outside of its part", i, min.X())
+		}
+	}
+	test.Equals(t, 10, len(groups[0]))
+	test.Equals(t, 0, len(groups[1]))
+	test.Equals(t, 5, len(groups[2]))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = clip.FillParts(ctx, pattern, 1, parts)
+	test.Assert(t, errors.Is(err, context.Canceled), "expected the context error but got %v", err)
+}
+
+func TestCompensateElephantFoot(t *testing.T) {
+	part := data.NewBasicLayerPart(rectangle(0, 0, 10000, 10000), data.Paths{reversed(rectangle(4000, 4000, 6000, 6000))})
+	part.SetExtruder(1)
+	small := data.NewBasicLayerPart(rectangle(20000, 0, 20150, 150), nil)
+
+	layer, err := clip.CompensateElephantFoot(data.NewPartitionedLayer([]data.LayerPart{part, small}), 100)
+	test.Ok(t, err)
+
+	// the small part collapses and is dropped
+	parts := layer.LayerParts()
+	test.Equals(t, 1, len(parts))
+	test.Equals(t, 1, parts[0].Extruder())
+
+	// only the outline is moved inwards
+	min, max := parts[0].Outline().Bounds()
+	test.Equals(t, []data.Micrometer{100, 100, 9900, 9900}, []data.Micrometer{min.X(), min.Y(), max.X(), max.Y()})
+
+	test.Equals(t, 1, len(parts[0].Holes()))
+	min, max = parts[0].Holes()[0].Bounds()
+	test.Equals(t, []data.Micrometer{4000, 4000, 6000, 6000}, []data.Micrometer{min.X(), min.Y(), max.X(), max.Y()})
+
+	// no amount keeps the layer
+	unchanged := data.NewPartitionedLayer([]data.LayerPart{part, small})
+	layer, err = clip.CompensateElephantFoot(unchanged, 0)
+	test.Ok(t, err)
+	test.Equals(t, 2, len(layer.LayerParts()))
+}
+
+func BenchmarkGridPattern(b *testing.B) {
+	hole := reversed(rectangle(40000, 40000, 60000, 60000))
+	part := data.NewBasicLayerPart(rectangle(0, 0, 100000, 100000), data.Paths{hole})
+	// a cross pattern without gaps is a grid
+	pattern := clip.NewCrossPattern(400, 2000, 45, 0, 0)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := pattern.Fill(context.Background(), 0, part); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestMultiLinePattern(t *testing.T) {
+	part := data.NewBasicLayerPart(rectangle(500, 500, 10500, 10500), nil)
+
+	var tests = []struct {
+		degrees  []int
+		expLines int
+	}{
+		{degrees: []int{0}, expLines: 10},
+		{degrees: []int{0, 90}, expLines: 20},
+		// the same lines are only generated once
+		{degrees: []int{0, 180, 90, -90}, expLines: 20},
+	}
+
+	for i, testCase := range tests {
+		t.Log("testCase", i)
+
+		pattern := clip.NewMultiLinePattern(400, 1000, testCase.degrees)
+		paths, err := pattern.Fill(context.Background(), 0, part)
+		test.Ok(t, err)
+		test.Equals(t, testCase.expLines, len(paths))
+	}
+
+	// the diagonal families of triangles cross the square with more lines than a grid
+	paths, err := clip.NewMultiLinePattern(400, 1000, []int{0, 60, 120}).Fill(context.Background(), 0, part)
+	test.Ok(t, err)
+	test.Assert(t, len(paths) > 30, "expected more than 30 lines but got %v", len(paths))
+}
+
+func TestOffset(t *testing.T) {
+	c := clip.NewClipper()
+	square := data.Paths{rectangle(0, 0, 10000, 10000)}
+
+	var tests = []struct {
+		distance data.Micrometer
+		join     clip.JoinType
+		expMin   data.Micrometer
+		expMax   data.Micrometer
+		expCount int
+	}{
+		{distance: 500, join: clip.SquareJoin, expMin: -500, expMax: 10500, expCount: 1},
+		{distance: 500, join: clip.MiterJoin, expMin: -500, expMax: 10500, expCount: 1},
+		{distance: 500, join: clip.RoundJoin, expMin: -500, expMax: 10500, expCount: 1},
+		{distance: -500, join: clip.SquareJoin, expMin: 500, expMax: 9500, expCount: 1},
+		// collapsing polygons are dropped
+		{distance: -6000, join: clip.SquareJoin, expCount: 0},
+	}
+
+	for i, testCase := range tests {
+		t.Log("testCase", i)
+
+		result, err := c.Offset(square, testCase.distance, testCase.join)
+		test.Ok(t, err)
+		test.Equals(t, testCase.expCount, len(result))
+		if testCase.expCount == 0 {
+			continue
+		}
+
+		min, max := result.Bounds()
+		test.Equals(t, []data.Micrometer{testCase.expMin, testCase.expMin, testCase.expMax, testCase.expMax}, []data.Micrometer{min.X(), min.Y(), max.X(), max.Y()})
+	}
+
+	// only the miter join keeps the corners sharp
+	miter, err := c.Offset(square, 500, clip.MiterJoin)
+	test.Ok(t, err)
+	test.Equals(t, 4, len(miter[0]))
+	squared, err := c.Offset(square, 500, clip.SquareJoin)
+	test.Ok(t, err)
+	test.Equals(t, 8, len(squared[0]))
+
+	// a lower arc tolerance results in more points for round joins
+	coarse, err := c.Offset(square, 500, clip.RoundJoin, clip.WithArcTolerance(100))
+	test.Ok(t, err)
+	fine, err := c.Offset(square, 500, clip.RoundJoin, clip.WithArcTolerance(1))
+	test.Ok(t, err)
+	test.Assert(t, len(fine[0]) > len(coarse[0]), "expected more points with the lower tolerance")
+
+	// coordinates which clipper can not process are rejected
+	_, err = c.Offset(data.Paths{rectangle(0, 0, clip.MaxCoordinate+1, 1000)}, 500, clip.SquareJoin)
+	var coordinateErr *clip.CoordinateError
+	test.Assert(t, errors.As(err, &coordinateErr), "expected a *CoordinateError but got %v", err)
+}
+
+func TestNestingDepth(t *testing.T) {
+	polygons := data.Paths{
+		rectangle(0, 0, 10000, 10000),
+		reversed(rectangle(1000, 1000, 9000, 9000)),
+		// an island inside of the hole
+		rectangle(2000, 2000, 8000, 8000),
+		reversed(rectangle(3000, 3000, 7000, 7000)),
+		// an island inside of the hole of the island
+		rectangle(4000, 4000, 6000, 6000),
+		// a separate part
+		rectangle(20000, 0, 30000, 10000),
+	}
+
+	c := clip.NewClipper()
+	result, err := c.GenerateLayerParts(context.Background(), layer{polygons: polygons}, clip.WithNestingDepth())
+	test.Ok(t, err)
+
+	var depths []int
+	for _, part := range result.LayerParts() {
+		depth, ok := clip.NestingDepth(part)
+		test.Assert(t, ok, "the depth should be available")
+		depths = append(depths, depth)
+	}
+	// the parts are ordered by their min corner
+	test.Equals(t, []int{0, 1, 2, 0}, depths)
+
+	// by default no depth is recorded
+	result, err = c.GenerateLayerParts(context.Background(), layer{polygons: polygons})
+	test.Ok(t, err)
+	_, ok := clip.NestingDepth(result.LayerParts()[0])
+	test.Assert(t, !ok, "the depth should not be available")
+}
+
+func TestAttributesKeepInput(t *testing.T) {
+	part := data.NewBasicLayerPart(rectangle(0, 0, 10000, 10000), nil)
+	part.SetExtruder(1)
+
+	tracked, err := clip.NewPartTracker().Track([]data.LayerPart{part})
+	test.Ok(t, err)
+	insets := clip.NewClipper().Inset(part, 400, 1)
+
+	// the results start with the extruder of the input part,
+	// but changing their extruder does not change the input part
+	for _, result := range []data.LayerPart{tracked[0], insets[0][0]} {
+		test.Equals(t, 1, result.Extruder())
+		result.SetExtruder(2)
+		test.Equals(t, 2, result.Extruder())
+		test.Equals(t, 1, part.Extruder())
+	}
+}
+
+func TestLinearPatternOnGrid(t *testing.T) {
+	pattern, err := clip.NewLinearPatternOnGrid(400, 1000, data.NewMicroPoint(300, 0), 0, 0)
+	test.Ok(t, err)
+
+	// the lines of differently sized parts are on the same grid
+	for i, part := range []data.LayerPart{
+		data.NewBasicLayerPart(rectangle(0, 0, 5000, 5000), nil),
+		data.NewBasicLayerPart(rectangle(1700, 0, 3400, 10000), nil),
+		// the part starts just before a grid line, so the line at its edge must not be dropped
+		data.NewBasicLayerPart(rectangle(250, 0, 2400, 1000), nil),
+	} {
+		t.Log("part", i)
+
+		// layer 1 is not rotated
+		paths, err := pattern.Fill(context.Background(), 1, part)
+		test.Ok(t, err)
+
+		min, max := part.Outline().Bounds()
+		expLines := 0
+		for x := data.Micrometer(300); x < max.X(); x += 1000 {
+			if x > min.X() {
+				expLines++
+			}
+		}
+		test.Equals(t, expLines, len(paths))
+
+		for _, path := range paths {
+			test.Equals(t, data.Micrometer(300), path[0].X()%1000)
+		}
+	}
+}
+
+func TestInsetRole(t *testing.T) {
+	part := data.NewBasicLayerPart(rectangle(0, 0, 10000, 10000), data.Paths{reversed(rectangle(4000, 4000, 6000, 6000))})
+	c := clip.NewClipper()
+
+	insets := c.Inset(part, 400, 3)
+	test.Equals(t, 3, len(insets))
+
+	for insetNr, inset := range insets {
+		expRole := clip.InnerWall
+		if insetNr == 0 {
+			expRole = clip.OuterWall
+		}
+
+		for _, insetPart := range inset {
+			role, ok := clip.Role(insetPart)
+			test.Assert(t, ok, "inset %v should have a role", insetNr)
+			test.Equals(t, expRole, role)
+		}
+	}
+
+	_, ok := clip.Role(part)
+	test.Assert(t, !ok, "the original part should have no role")
+}
+
+func TestSupportPillars(t *testing.T) {
+	support := data.Paths{rectangle(0, 0, 10000, 5100)}
+
+	// the grid intersections inside are at x = 2000 ... 8000 and y = 2000, 4000
+	pillars, err := clip.SupportPillars(support, 2000, 1000)
+	test.Ok(t, err)
+	test.Equals(t, 8, len(pillars))
+
+	for _, pillar := range pillars {
+		min, max := pillar.Bounds()
+		test.Equals(t, data.Micrometer(1000), max.X()-min.X())
+		test.Equals(t, data.Micrometer(1000), max.Y()-min.Y())
+	}
+
+	// pillars near an edge are clipped
+	pillars, err = clip.SupportPillars(data.Paths{rectangle(0, 0, 10000, 4300)}, 2000, 1000)
+	test.Ok(t, err)
+	test.Equals(t, 8, len(pillars))
+	_, max := data.Paths(pillars).Bounds()
+	test.Equals(t, data.Micrometer(4300), max.Y())
+
+	pillars, err = clip.SupportPillars(support, 0, 1000)
+	test.Ok(t, err)
+	test.Equals(t, 0, len(pillars))
+}
+
+func TestMergedPattern(t *testing.T) {
+	part := data.NewBasicLayerPart(rectangle(0, 0, 10000, 10000), data.Paths{
+		// a sliver which is much thinner than a line
+		reversed(rectangle(2000, 6000, 8000, 6020)),
+		// a real hole
+		reversed(rectangle(2000, 2000, 8000, 3000)),
+	})
+	pattern := clip.NewLinearPattern(400, 1000, data.NewMicroPoint(500, 500), data.NewMicroPoint(100000, 100000), 0)
+
+	// layer 1 is not rotated, so 6 of the 10 vertical lines cross both holes
+	paths, err := pattern.Fill(context.Background(), 1, part)
+	test.Ok(t, err)
+	test.Equals(t, 22, len(paths))
+
+	// only the gaps of the sliver are closed
+	paths, err = clip.NewMergedPattern(pattern, 100).Fill(context.Background(), 1, part)
+	test.Ok(t, err)
+	test.Equals(t, 16, len(paths))
+
+	var length data.Micrometer
+	for _, path := range paths {
+		test.Equals(t, 2, len(path))
+		length += path.Length(false)
+	}
+	test.Equals(t, data.Micrometer(10*10000-6*1000), length)
+
+	// the same works for rotated lines
+	rotated := clip.NewLinearPattern(400, 1000, data.NewMicroPoint(500, 500), data.NewMicroPoint(100000, 100000), 45)
+	unmerged, err := rotated.Fill(context.Background(), 1, part)
+	test.Ok(t, err)
+	paths, err = clip.NewMergedPattern(rotated, 100).Fill(context.Background(), 1, part)
+	test.Ok(t, err)
+	test.Assert(t, len(paths) < len(unmerged), "expected less than %v lines but got %v", len(unmerged), len(paths))
+}
+
+func TestPartTracker(t *testing.T) {
+	tracker := clip.NewPartTracker()
+
+	ids := func(parts []data.LayerPart) []int {
+		var result []int
+		for _, part := range parts {
+			id, ok := clip.PartID(part)
+			test.Assert(t, ok, "the part should have an id")
+			result = append(result, id)
+		}
+		return result
+	}
+
+	layers := []struct {
+		parts  []data.LayerPart
+		expIDs []int
+	}{
+		{
+			parts: []data.LayerPart{
+				data.NewBasicLayerPart(rectangle(0, 0, 10000, 10000), nil),
+				data.NewBasicLayerPart(rectangle(20000, 0, 30000, 10000), nil),
+			},
+			expIDs: []int{0, 1},
+		},
+		// the order and the shape changes
+		{
+			parts: []data.LayerPart{
+				data.NewBasicLayerPart(rectangle(21000, 0, 29000, 9000), nil),
+				data.NewBasicLayerPart(rectangle(1000, 0, 9000, 9000), nil),
+				data.NewBasicLayerPart(rectangle(50000, 0, 60000, 10000), nil),
+			},
+			expIDs: []int{1, 0, 2},
+		},
+		// the first part splits, the bigger piece keeps the id
+		{
+			parts: []data.LayerPart{
+				data.NewBasicLayerPart(rectangle(21000, 0, 24000, 9000), nil),
+				data.NewBasicLayerPart(rectangle(25000, 0, 29000, 9000), nil),
+			},
+			expIDs: []int{3, 1},
+		},
+		// both pieces merge again
+		{
+			parts: []data.LayerPart{
+				data.NewBasicLayerPart(rectangle(21000, 0, 29000, 9000), nil),
+			},
+			expIDs: []int{1},
+		},
+	}
+
+	for i, layer := range layers {
+		t.Log("layer", i)
+		parts, err := tracker.Track(layer.parts)
+		test.Ok(t, err)
+		test.Equals(t, layer.expIDs, ids(parts))
+	}
+}
+
+func TestBorderedPattern(t *testing.T) {
+	part := data.NewBasicLayerPart(rectangle(0, 0, 10000, 10000), nil)
+	pattern := clip.NewBorderedPattern(clip.NewLinearPattern(400, 1000, data.NewMicroPoint(500, 500), data.NewMicroPoint(100000, 100000), 0), 400)
+
+	paths, err := pattern.Fill(context.Background(), 1, part)
+	test.Ok(t, err)
+	test.Assert(t, len(paths) > 1, "expected the loop and the lines")
+
+	// the first path is the closed loop half a line width inside of the part
+	loop := paths[0]
+	test.Equals(t, 5, len(loop))
+	min, max := loop.Bounds()
+	test.Equals(t, []data.Micrometer{200, 200, 9800, 9800}, []data.Micrometer{min.X(), min.Y(), max.X(), max.Y()})
+
+	// the lines stay inside of the loop, so they don't overlap it
+	for _, line := range paths[1:] {
+		min, max := line.Bounds()
+		test.Assert(t, min.X() >= 400 && min.Y() >= 400 && max.X() <= 9600 && max.Y() <= 9600, "the line must be inside of the loop")
+	}
+
+	// a part which is too small for the loop is only filled by the pattern
+	paths, err = pattern.Fill(context.Background(), 1, data.NewBasicLayerPart(rectangle(0, 0, 300, 300), nil))
+	test.Ok(t, err)
+	test.Equals(t, 0, len(paths))
+}
+
+func TestInsetThinRing(t *testing.T) {
+	c := clip.NewClipper()
+
+	// the band of 1000 has room for the outer walls of the outline and the hole but not for more
+	wide := data.NewBasicLayerPart(rectangle(0, 0, 10000, 10000), data.Paths{reversed(rectangle(1000, 1000, 9000, 9000))})
+	insets := c.Inset(wide, 400, 3)
+	test.Equals(t, 3, len(insets))
+	test.Equals(t, 1, len(insets[0]))
+	test.Equals(t, 1, len(insets[0][0].Holes()))
+	test.Equals(t, 0, len(insets[1]))
+	test.Equals(t, 0, len(insets[2]))
+
+	// The band of 1500 has room for the outer walls, but the inner walls would overlap.
+	// Before, clipper returned them as thin ring.
+	deep := data.NewBasicLayerPart(rectangle(0, 0, 10000, 10000), data.Paths{reversed(rectangle(1500, 1500, 8500, 8500))})
+	insets = c.Inset(deep, 400, 3)
+	test.Equals(t, 1, len(insets[0]))
+	test.Equals(t, 1, len(insets[0][0].Holes()))
+	test.Equals(t, 0, len(insets[1]))
+	test.Equals(t, 0, len(insets[2]))
+
+	// The bands of 500 to 790 are thinner than two walls, so they would overlap.
+	// As nothing else of the ring is printed, the outer walls are kept anyway.
+	for _, width := range []data.Micrometer{500, 700, 790} {
+		t.Log("width", width)
+		ring := data.NewBasicLayerPart(rectangle(0, 0, 10000, 10000), data.Paths{reversed(rectangle(width, width, 10000-width, 10000-width))})
+		insets = c.Inset(ring, 400, 3)
+		test.Equals(t, 3, len(insets))
+		test.Equals(t, 1, len(insets[0]))
+		test.Equals(t, 1, len(insets[0][0].Holes()))
+		test.Equals(t, 0, len(insets[1]))
+		test.Equals(t, 0, len(insets[2]))
+	}
+	thin := data.NewBasicLayerPart(rectangle(0, 0, 10000, 10000), data.Paths{reversed(rectangle(700, 700, 9300, 9300))})
+
+	// optionally a single loop is placed in the middle of the band
+	insets = c.Inset(thin, 400, 3, clip.WithThinRingCenterLine())
+	test.Equals(t, 1, len(insets[0]))
+	test.Equals(t, 0, len(insets[0][0].Holes()))
+	min, max := insets[0][0].Outline().Bounds()
+	test.Equals(t, []data.Micrometer{350, 350, 9650, 9650}, []data.Micrometer{min.X(), min.Y(), max.X(), max.Y()})
+
+	role, ok := clip.Role(insets[0][0])
+	test.Assert(t, ok, "the center line should have a role")
+	test.Equals(t, clip.OuterWall, role)
+	test.Equals(t, 0, len(insets[1]))
+
+	// the loop follows the middle of the band if the hole is not centered
+	eccentric := data.NewBasicLayerPart(rectangle(0, 0, 10000, 10000), data.Paths{reversed(rectangle(500, 700, 9100, 9300))})
+	insets = c.Inset(eccentric, 400, 3, clip.WithThinRingCenterLine())
+	test.Equals(t, 1, len(insets[0]))
+	min, max = insets[0][0].Outline().Bounds()
+	test.Equals(t, []data.Micrometer{250, 350, 9550, 9650}, []data.Micrometer{min.X(), min.Y(), max.X(), max.Y()})
+	for _, point := range insets[0][0].Outline() {
+		inside := point.X() > 0 && point.X() < 500 || point.X() > 9100 && point.X() < 10000 ||
+			point.Y() > 0 && point.Y() < 700 || point.Y() > 9300 && point.Y() < 10000
+		test.Assert(t, inside, "the loop must stay inside of the band, got (%v|%v)", point.X(), point.Y())
+	}
+}
+
+func TestInsetThinRingIncremental(t *testing.T) {
+	c := clip.NewClipper()
+	ring := data.NewBasicLayerPart(rectangle(0, 0, 20000, 20000), data.Paths{reversed(rectangle(700, 700, 19300, 19300))})
+
+	// the center line is not inset any further, as it would be printed across the hole
+	insets := c.Inset(ring, 400, 3, clip.WithIncrementalInset(), clip.WithThinRingCenterLine())
+	test.Equals(t, 3, len(insets))
+	test.Equals(t, 1, len(insets[0]))
+	min, max := insets[0][0].Outline().Bounds()
+	test.Equals(t, []data.Micrometer{350, 350, 19650, 19650}, []data.Micrometer{min.X(), min.Y(), max.X(), max.Y()})
+	test.Equals(t, 0, len(insets[1]))
+	test.Equals(t, 0, len(insets[2]))
+}
@@ -0,0 +1,121 @@
+// This file implements a concentric pattern infill.
+
+package clip
+
+import (
+	"GoSlice/data"
+	"context"
+
+	clipper "github.com/aligator/go.clipper"
+)
+
+// concentric provides an infill which consists of loops following the outline and the holes of the part.
+// This avoids the stair-step edges of linear infill on round parts.
+type concentric struct {
+	lineDistance data.Micrometer
+	lineWidth    data.Micrometer
+}
+
+// NewConcentricPattern provides an infill pattern consisting of loops which are offset
+// inwards from the outline (and outwards from the holes) of the filled part.
+// For solid surfaces just use the lineWidth as lineDistance.
+//
+// If the remaining area in the middle is too small for another loop, it is filled with a short line.
+// Remaining areas which are smaller than a line are left empty.
+func NewConcentricPattern(lineWidth data.Micrometer, lineDistance data.Micrometer) Pattern {
+	return concentric{
+		lineDistance: lineDistance,
+		lineWidth:    lineWidth,
+	}
+}
+
+// Fill implements the Pattern interface by using concentric loops as infill.
+// The loops are closed by repeating the first point at the end of each path.
+func (p concentric) Fill(ctx context.Context, layerNr int, part data.LayerPart) (data.Paths, error) {
+	if err := validateParts([]data.LayerPart{part}); err != nil {
+		return nil, err
+	}
+
+	if p.lineDistance <= 0 {
+		return nil, nil
+	}
+
+	parts := []data.LayerPart{part}
+
+	var result data.Paths
+	var lastLoops []data.LayerPart
+	for distance := p.lineWidth / 2; ; distance += p.lineDistance {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		loops := offsetParts(parts, -distance)
+		if len(loops) == 0 {
+			break
+		}
+
+		for _, loop := range loops {
+			result = append(result, closedLoop(loop.Outline()))
+			for _, hole := range loop.Holes() {
+				result = append(result, closedLoop(hole))
+			}
+		}
+		lastLoops = loops
+	}
+
+	remnant, err := p.fillRemnant(lastLoops)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(result, remnant...), nil
+}
+
+// fillRemnant fills the area inside of the most inner loops which is not covered by the loops
+// but too small for another loop, using a short line through the middle of it.
+func (p concentric) fillRemnant(lastLoops []data.LayerPart) (data.Paths, error) {
+	remnants := offsetParts(lastLoops, -p.lineWidth/2)
+
+	var result data.Paths
+	for _, remnant := range remnants {
+		// ignore remnants which are smaller than a line
+		if clipper.Area(clipperPath(remnant.Outline())) < float64(p.lineWidth*p.lineWidth) {
+			continue
+		}
+
+		// use a line along the longer side of the bounding box
+		min, max := remnant.Outline().Bounds()
+		rotation := 0.0
+		if max.Y()-min.Y() < max.X()-min.X() {
+			rotation = 90
+		}
+
+		outline, holes := rotatedCopy(remnant, rotation)
+		min, max = outline.Bounds()
+		center := (min.X() + max.X()) / 2
+
+		// generate exactly one line in the center and clip it by the remnant
+		line := verticalLines(min, max, center, max.X()-min.X()+1)
+		clipped, err := clipLines(clipperPath(outline), clipperPaths(holes), line, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		lineResult := microPaths(clipped, false)
+		lineResult.Rotate(-rotation)
+		result = append(result, lineResult...)
+	}
+
+	return result, nil
+}
+
+// closedLoop returns a copy of the polygon with the first point appended at the end.
+func closedLoop(polygon data.Path) data.Path {
+	if len(polygon) == 0 {
+		return polygon
+	}
+
+	loop := make(data.Path, len(polygon), len(polygon)+1)
+	copy(loop, polygon)
+	return append(loop, polygon[0])
+}
@@ -0,0 +1,97 @@
+// This file implements a cross infill pattern which is flexible because of periodic gaps.
+
+package clip
+
+import (
+	"GoSlice/data"
+	"context"
+
+	clipper "github.com/aligator/go.clipper"
+)
+
+// cross provides an infill which consists of two perpendicular line families
+// where segments are left out periodically.
+// In contrast to a grid the lines are not connected over long distances, so the infill stays flexible.
+type cross struct {
+	lineDistance data.Micrometer
+	lineWidth    data.Micrometer
+	degree       int
+	skipPeriod   int
+	skipPhase    int
+}
+
+// NewCrossPattern provides an infill pattern consisting of disconnected crosses, which is useful for flexible filaments.
+//
+// Each line is divided into segments of the length lineDistance.
+// Every skipPeriod-th segment is left out, starting with the segment skipPhase.
+// If skipPeriod is <= 0, no segment is left out and the result is a normal grid.
+// The left out segments are the same on all layers, so the crosses stack on each other.
+func NewCrossPattern(lineWidth data.Micrometer, lineDistance data.Micrometer, degree int, skipPeriod int, skipPhase int) Pattern {
+	return cross{
+		lineDistance: lineDistance,
+		lineWidth:    lineWidth,
+		degree:       degree,
+		skipPeriod:   skipPeriod,
+		skipPhase:    skipPhase,
+	}
+}
+
+// Fill implements the Pattern interface by using two perpendicular line families with gaps as infill.
+func (p cross) Fill(ctx context.Context, layerNr int, part data.LayerPart) (data.Paths, error) {
+	if err := validateParts([]data.LayerPart{part}); err != nil {
+		return nil, err
+	}
+
+	if p.lineDistance <= 0 {
+		return nil, nil
+	}
+
+	angles := []float64{float64(p.degree), float64(p.degree + 90)}
+	return fillLineFamilies(ctx, part, angles, p.lineDistance, 0, p.skipSegments)
+}
+
+// skipSegments splits the vertical lines into the segments which are not skipped.
+// The segments are aligned to the origin, so that neighboring parts and layers line up.
+// Consecutive segments which are not skipped are combined into one line.
+func (p cross) skipSegments(lines clipper.Paths) clipper.Paths {
+	if p.skipPeriod <= 0 {
+		return lines
+	}
+
+	var result clipper.Paths
+	for _, line := range lines {
+		x := line[0].X
+		top := data.Micrometer(line[0].Y)
+		bottom := data.Micrometer(line[1].Y)
+
+		var start data.Micrometer
+		inSegment := false
+		for y := alignToGrid(bottom, p.lineDistance, 0); y < top; y += p.lineDistance {
+			segment := int(y / p.lineDistance)
+			skipped := ((segment-p.skipPhase)%p.skipPeriod+p.skipPeriod)%p.skipPeriod == 0
+
+			switch {
+			case skipped && inSegment:
+				result = append(result, verticalSegment(x, start, y))
+				inSegment = false
+			case !skipped && !inSegment:
+				start = y
+				inSegment = true
+			}
+		}
+
+		if inSegment {
+			result = append(result, verticalSegment(x, start, top))
+		}
+	}
+
+	return result
+}
+
+// verticalSegment returns a line from bottom to top at the given x coordinate.
+func verticalSegment(x clipper.CInt, bottom data.Micrometer, top data.Micrometer) clipper.Path {
+	return clipper.Path{
+		&clipper.IntPoint{X: x, Y: clipper.CInt(top)},
+		&clipper.IntPoint{X: x, Y: clipper.CInt(bottom)},
+	}
+}
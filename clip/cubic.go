@@ -0,0 +1,69 @@
+// This file implements a cubic infill pattern which changes with the layer height.
+
+package clip
+
+import (
+	"GoSlice/data"
+	"context"
+	"math"
+)
+
+// cubic provides an infill which consists of three families of parallel lines rotated by 120° to each other.
+// The lines of each family are shifted depending on the z height of the layer,
+// so that the printed infill forms cubes standing on one of their corners.
+type cubic struct {
+	lineDistance          data.Micrometer
+	lineWidth             data.Micrometer
+	degree                int
+	initialLayerThickness data.Micrometer
+	layerThickness        data.Micrometer
+}
+
+// NewCubicPattern provides a 3d infill pattern which consists of cubes standing on one of their corners.
+// This gives the infill strength in all three axes.
+//
+// The lineDistance is the distance a linear pattern of the same density would use.
+// As the cubic pattern consists of three line families, each family uses three times this distance.
+// The layer thicknesses are needed to calculate the z height of each layer.
+// The line families are shifted according to this height, so that the walls of the cubes continue across the layers.
+func NewCubicPattern(lineWidth data.Micrometer, lineDistance data.Micrometer, degree int, initialLayerThickness data.Micrometer, layerThickness data.Micrometer) Pattern {
+	return cubic{
+		lineDistance:          lineDistance,
+		lineWidth:             lineWidth,
+		degree:                degree,
+		initialLayerThickness: initialLayerThickness,
+		layerThickness:        layerThickness,
+	}
+}
+
+// Fill implements the Pattern interface by using three shifted line families as infill.
+func (p cubic) Fill(ctx context.Context, layerNr int, part data.LayerPart) (data.Paths, error) {
+	if err := validateParts([]data.LayerPart{part}); err != nil {
+		return nil, err
+	}
+
+	z := p.initialLayerThickness + data.Micrometer(layerNr)*p.layerThickness
+
+	familyDistance := p.lineDistance * 3
+	if familyDistance <= 0 {
+		return nil, nil
+	}
+
+	// A cube standing on its corner intersects the horizontal plane with lines
+	// which move by z / sqrt(2) while the z height increases.
+	shift := data.Micrometer(float64(z)/math.Sqrt2) % familyDistance
+
+	angles := []float64{float64(p.degree), float64(p.degree + 120), float64(p.degree + 240)}
+	return fillLineFamilies(ctx, part, angles, familyDistance, shift, nil)
+}
+
+// alignToGrid returns the biggest value which is <= value and lies on the grid
+// defined by the given distance and offset (offset + k * distance).
+func alignToGrid(value data.Micrometer, distance data.Micrometer, offset data.Micrometer) data.Micrometer {
+	diff := (value - offset) % distance
+	if diff < 0 {
+		diff += distance
+	}
+
+	return value - diff
+}
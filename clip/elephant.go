@@ -0,0 +1,57 @@
+// This file provides the compensation of the elephant foot of the first layers.
+
+package clip
+
+import (
+	"GoSlice/data"
+
+	clipper "github.com/aligator/go.clipper"
+)
+
+// CompensateElephantFoot shrinks the outlines of all parts of the layer by the given amount
+// to counter the squish-out of the first layers (the so called elephant foot).
+// Callers usually apply a decreasing amount to the first few layers.
+//
+// Only the outlines are moved inwards, the holes are kept as they are.
+// Parts which collapse by the offset are dropped, so small parts are not inverted.
+// A part may also be split into several parts if its outline gets too narrow.
+// The extruder of each part is kept, but the attributes of the layer are not.
+//
+// If the amount is <= 0, the layer is returned unchanged.
+// If a coordinate exceeds MaxCoordinate, a *CoordinateError is returned.
+// If clipper fails, a *ClipError is returned.
+func CompensateElephantFoot(layer data.PartitionedLayer, amount data.Micrometer) (data.PartitionedLayer, error) {
+	if amount <= 0 {
+		return layer, nil
+	}
+	if err := validateParts(layer.LayerParts()); err != nil {
+		return nil, err
+	}
+
+	c := clipperClipper{}
+
+	var result []data.LayerPart
+	for _, part := range layer.LayerParts() {
+		shrunk := offsetToParts(clipper.Paths{clipperPath(part.Outline())}, float64(-amount), MiterJoin, options{})
+
+		if len(part.Holes()) > 0 && len(shrunk) > 0 {
+			var holes []data.LayerPart
+			for _, hole := range part.Holes() {
+				holes = append(holes, data.NewBasicLayerPart(hole, nil))
+			}
+
+			withHoles, ok := c.Difference(shrunk, holes)
+			if !ok {
+				return nil, newClipError(clipper.CtDifference, partsToClipperPaths(shrunk), clipperPaths(part.Holes()))
+			}
+			shrunk = withHoles
+		}
+
+		for _, shrunkPart := range shrunk {
+			shrunkPart.SetExtruder(part.Extruder())
+		}
+		result = append(result, shrunk...)
+	}
+
+	return data.NewPartitionedLayer(sortLayerParts(result)), nil
+}
@@ -0,0 +1,81 @@
+// This file provides the errors returned by the clip package.
+
+package clip
+
+import (
+	"GoSlice/data"
+	"fmt"
+
+	clipper "github.com/aligator/go.clipper"
+)
+
+// ClipError is returned if the external clipper lib fails to execute an operation.
+// It contains some information about the input which may help to find the problematic layer.
+type ClipError struct {
+	// Operation is the clip type which was attempted, e.g. "union" or "intersection".
+	Operation string
+
+	// PolygonCount is the number of input polygons.
+	PolygonCount int
+
+	// PointCount is the number of points of all input polygons.
+	PointCount int
+
+	// Min and Max describe the bounding box of all input polygons.
+	Min, Max data.MicroPoint
+}
+
+// newClipError creates a new ClipError for the given clip type and input paths.
+func newClipError(clipType clipper.ClipType, input ...clipper.Paths) *ClipError {
+	var all data.Paths
+	pointCount := 0
+	for _, paths := range input {
+		for _, path := range paths {
+			pointCount += len(path)
+		}
+		all = append(all, microPaths(paths, false)...)
+	}
+
+	min, max := all.Bounds()
+
+	return &ClipError{
+		Operation:    clipTypeName(clipType),
+		PolygonCount: len(all),
+		PointCount:   pointCount,
+		Min:          min,
+		Max:          max,
+	}
+}
+
+func (e *ClipError) Error() string {
+	return fmt.Sprintf("clipper %v failed for %v polygons with %v points in the bounding box (%v|%v) - (%v|%v)",
+		e.Operation, e.PolygonCount, e.PointCount, e.Min.X(), e.Min.Y(), e.Max.X(), e.Max.Y())
+}
+
+// CoordinateError is returned if a coordinate exceeds MaxCoordinate
+// and can therefore not be processed safely by clipper.
+type CoordinateError struct {
+	// Point is the first point which exceeds the range.
+	Point data.MicroPoint
+}
+
+func (e *CoordinateError) Error() string {
+	return fmt.Sprintf("the point (%v|%v) exceeds the maximum coordinate of ±%v micrometers, the model is too large",
+		e.Point.X(), e.Point.Y(), MaxCoordinate)
+}
+
+// clipTypeName returns a readable name of the given clip type.
+func clipTypeName(clipType clipper.ClipType) string {
+	switch clipType {
+	case clipper.CtIntersection:
+		return "intersection"
+	case clipper.CtUnion:
+		return "union"
+	case clipper.CtDifference:
+		return "difference"
+	case clipper.CtXor:
+		return "xor"
+	default:
+		return "unknown operation"
+	}
+}
@@ -0,0 +1,79 @@
+// This file provides the detection of parts which are not connected to the build plate.
+
+package clip
+
+import (
+	"GoSlice/data"
+	"math"
+
+	clipper "github.com/aligator/go.clipper"
+)
+
+// GroundedParts labels each part of the given layers as grounded or floating.
+// The layers contain the parts of consecutive layers, starting with the first layer
+// (e.g. the LayerParts of each data.PartitionedLayer).
+//
+// All parts of the first layer are grounded as they are on the build plate.
+// A part of any other layer is grounded if it overlaps the grounded parts
+// of the layer below by at least minOverlapArea (in square micrometers).
+// All other parts are floating islands which appear mid-print and need support below them.
+//
+// The result contains a value for each part in the same order as the input: result[layerNr][partNr].
+// If a coordinate exceeds MaxCoordinate, a *CoordinateError is returned.
+// If clipper fails, a *ClipError is returned.
+func GroundedParts(layers [][]data.LayerPart, minOverlapArea float64) ([][]bool, error) {
+	for _, parts := range layers {
+		if err := validateParts(parts); err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([][]bool, len(layers))
+
+	c := clipperClipper{}
+	var groundedBelow []data.LayerPart
+	for layerNr, parts := range layers {
+		result[layerNr] = make([]bool, len(parts))
+
+		var grounded []data.LayerPart
+		for partNr, part := range parts {
+			if layerNr == 0 {
+				result[layerNr][partNr] = true
+				grounded = append(grounded, part)
+				continue
+			}
+
+			if len(groundedBelow) == 0 {
+				continue
+			}
+
+			overlap, ok := c.Intersection([]data.LayerPart{part}, groundedBelow)
+			if !ok {
+				return nil, newClipError(clipper.CtIntersection, partsToClipperPaths([]data.LayerPart{part}), partsToClipperPaths(groundedBelow))
+			}
+
+			area := partsArea(overlap)
+			if area > 0 && area >= minOverlapArea {
+				result[layerNr][partNr] = true
+				grounded = append(grounded, part)
+			}
+		}
+
+		groundedBelow = grounded
+	}
+
+	return result, nil
+}
+
+// partsArea returns the area of all parts without their holes.
+func partsArea(parts []data.LayerPart) float64 {
+	var area float64
+	for _, part := range parts {
+		area += math.Abs(clipper.Area(clipperPath(part.Outline())))
+		for _, hole := range part.Holes() {
+			area -= math.Abs(clipper.Area(clipperPath(hole)))
+		}
+	}
+
+	return area
+}
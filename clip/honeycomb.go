@@ -0,0 +1,133 @@
+// This file implements a honeycomb infill pattern.
+
+package clip
+
+import (
+	"GoSlice/data"
+	"context"
+	"math"
+
+	clipper "github.com/aligator/go.clipper"
+)
+
+// honeycomb provides an infill which consists of a grid of regular hexagons.
+type honeycomb struct {
+	lineDistance data.Micrometer
+	lineWidth    data.Micrometer
+}
+
+// NewHoneycombPattern provides an infill pattern consisting of hexagons.
+//
+// The lineDistance is the distance a linear pattern of the same density would use.
+// The edge length of the hexagons is derived from it, so that the same amount of material is used.
+//
+// The hexagon grid has one of its hexagons centered on the origin and is the same for every layer,
+// so the hexagons of neighboring parts line up and the walls stack on each other.
+func NewHoneycombPattern(lineWidth data.Micrometer, lineDistance data.Micrometer) Pattern {
+	return honeycomb{
+		lineDistance: lineDistance,
+		lineWidth:    lineWidth,
+	}
+}
+
+// hexVertex identifies a vertex of the hexagon grid.
+// The x index is in units of half the hexagon width and the y index in units of half the edge length.
+// As all coordinates are calculated from these indices, shared vertices always get exactly the same coordinates.
+type hexVertex struct {
+	x, y int
+}
+
+// hexEdge is an edge of the hexagon grid with its vertices in a canonical order.
+type hexEdge struct {
+	a, b hexVertex
+}
+
+// newHexEdge returns the edge between the two vertices, independent of their order.
+func newHexEdge(a, b hexVertex) hexEdge {
+	if a.x > b.x || (a.x == b.x && a.y > b.y) {
+		a, b = b, a
+	}
+
+	return hexEdge{a: a, b: b}
+}
+
+// Fill implements the Pattern interface by using a honeycomb as infill.
+// Each edge of the grid is returned only once, even if it is shared by two hexagons.
+func (p honeycomb) Fill(ctx context.Context, layerNr int, part data.LayerPart) (data.Paths, error) {
+	if err := validateParts([]data.LayerPart{part}); err != nil {
+		return nil, err
+	}
+
+	if p.lineDistance <= 0 {
+		return nil, nil
+	}
+
+	// A honeycomb with the edge length a uses 2 / (sqrt(3) * a) line length per area
+	// while parallel lines use 1 / lineDistance.
+	edgeLength := 2 * float64(p.lineDistance) / math.Sqrt(3)
+	halfWidth := edgeLength * math.Sqrt(3) / 2
+	halfEdge := edgeLength / 2
+
+	min, max := part.Outline().Bounds()
+
+	// the rows are 1.5 edge lengths apart and the columns one hexagon width
+	minRow := int(math.Floor(float64(min.Y())/(3*halfEdge))) - 1
+	maxRow := int(math.Ceil(float64(max.Y())/(3*halfEdge))) + 1
+	minColumn := int(math.Floor(float64(min.X())/(2*halfWidth))) - 1
+	maxColumn := int(math.Ceil(float64(max.X())/(2*halfWidth))) + 1
+
+	seen := map[hexEdge]bool{}
+	var lines clipper.Paths
+
+	for row := minRow; row <= maxRow; row++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		for column := minColumn; column <= maxColumn; column++ {
+			// every second row is shifted by half a hexagon
+			x := 2 * column
+			if row%2 != 0 {
+				x++
+			}
+			y := 3 * row
+
+			corners := [6]hexVertex{
+				{x, y + 2},
+				{x + 1, y + 1},
+				{x + 1, y - 1},
+				{x, y - 2},
+				{x - 1, y - 1},
+				{x - 1, y + 1},
+			}
+
+			for i := range corners {
+				edge := newHexEdge(corners[i], corners[(i+1)%len(corners)])
+				if seen[edge] {
+					continue
+				}
+				seen[edge] = true
+
+				lines = append(lines, clipper.Path{
+					hexPoint(edge.a, halfWidth, halfEdge),
+					hexPoint(edge.b, halfWidth, halfEdge),
+				})
+			}
+		}
+	}
+
+	clipped, err := clipLines(clipperPath(part.Outline()), clipperPaths(part.Holes()), lines, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return microPaths(clipped, false), nil
+}
+
+// hexPoint converts the vertex indices to a point.
+func hexPoint(v hexVertex, halfWidth float64, halfEdge float64) *clipper.IntPoint {
+	return &clipper.IntPoint{
+		X: clipper.CInt(math.Round(float64(v.x) * halfWidth)),
+		Y: clipper.CInt(math.Round(float64(v.y) * halfEdge)),
+	}
+}
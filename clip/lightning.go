@@ -0,0 +1,89 @@
+// This file implements a lightning infill which only supports the top surfaces.
+
+package clip
+
+import (
+	"GoSlice/data"
+	"context"
+	"errors"
+)
+
+// Lightning generates an infill which is dense directly below top surfaces
+// and gets smaller for each layer further down, until it vanishes.
+// This saves a lot of material compared to a normal infill over the whole model.
+//
+// In contrast to a Pattern the Lightning infill is stateful, as the infill area of a layer
+// depends on the infill area of the layer above.
+// So Fill has to be called for each layer in descending order, starting at the top layer.
+// Use a new instance for each model.
+type Lightning struct {
+	c              clipperClipper
+	pattern        Pattern
+	shrinkDistance data.Micrometer
+
+	// supported is the area which was filled in the last (upper) layer.
+	supported []data.LayerPart
+}
+
+// NewLightning returns a new lightning infill generator.
+//
+// The lineWidth, lineDistance, min, max and degree are used to fill the supported areas with linear lines (see NewLinearPattern).
+// The shrinkDistance is the distance by which the supported area gets smaller for each layer further down.
+func NewLightning(lineWidth data.Micrometer, lineDistance data.Micrometer, min data.MicroPoint, max data.MicroPoint, degree int, shrinkDistance data.Micrometer) *Lightning {
+	return &Lightning{
+		pattern:        NewLinearPattern(lineWidth, lineDistance, min, max, degree),
+		shrinkDistance: shrinkDistance,
+	}
+}
+
+// Fill generates the lightning infill for the next layer.
+// It has to be called for each layer from the top layer to the bottom layer.
+// The infill parts are the area of the current layer which may be filled (e.g. the "infill" attribute).
+// The top parts are the area of the layer above which is printed as top surface and therefore needs support (e.g. the "top" attribute).
+// It returns the infill paths of the current layer.
+// If a coordinate exceeds MaxCoordinate, a *CoordinateError is returned.
+func (l *Lightning) Fill(ctx context.Context, layerNr int, infill []data.LayerPart, top []data.LayerPart) (data.Paths, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := validateParts(infill); err != nil {
+		return nil, err
+	}
+	if err := validateParts(top); err != nil {
+		return nil, err
+	}
+
+	// Shrink the area of the layer above, so that the supported area tapers off downwards.
+	shrunk := offsetParts(l.supported, -l.shrinkDistance)
+
+	needed := shrunk
+	if len(top) > 0 {
+		var ok bool
+		needed, ok = l.c.Union(shrunk, top)
+		if !ok {
+			return nil, errors.New("could not union the top area with the supported area of the layer above")
+		}
+	}
+
+	if len(needed) == 0 || len(infill) == 0 {
+		l.supported = nil
+		return nil, nil
+	}
+
+	needed, ok := l.c.Intersection(needed, infill)
+	if !ok {
+		return nil, errors.New("could not intersect the supported area with the infill area")
+	}
+	l.supported = needed
+
+	var result data.Paths
+	for _, part := range needed {
+		paths, err := l.pattern.Fill(ctx, layerNr, part)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, paths...)
+	}
+
+	return result, nil
+}
@@ -4,7 +4,8 @@ package clip
 
 import (
 	"GoSlice/data"
-	"fmt"
+	"context"
+	"errors"
 
 	clipper "github.com/aligator/go.clipper"
 )
@@ -16,6 +17,25 @@ type linear struct {
 	lineWidth    data.Micrometer
 	degree       int
 	min, max     data.MicroPoint
+	overlap      data.Micrometer
+
+	// wallOverlap is only used by FillNextToWalls.
+	wallOverlap data.Micrometer
+
+	// onGrid places the lines on a global grid through the gridOrigin instead of the grid defined by min and max.
+	onGrid     bool
+	gridOrigin data.MicroPoint
+}
+
+// WallAwarePattern is a Pattern which can overlap the walls around the part
+// by a different amount than all other boundaries of the part (e.g. top skin or bridges).
+type WallAwarePattern interface {
+	Pattern
+
+	// FillNextToWalls fills the part like Fill, but the edges of the part which touch
+	// the given walls use the wall overlap of the pattern instead of the normal overlap.
+	// The walls are the regions which are covered by the perimeters next to the part.
+	FillNextToWalls(ctx context.Context, layerNr int, part data.LayerPart, walls []data.LayerPart) (data.Paths, error)
 }
 
 // NewLinearPattern provides a simple linear infill pattern consisting of simple parallel lines.
@@ -30,15 +50,176 @@ func NewLinearPattern(lineWidth data.Micrometer, lineDistance data.Micrometer, m
 	}
 }
 
+// NewLinearPatternWithOverlap provides the same pattern as NewLinearPattern but the lines
+// overlap the outline and the holes of the filled part by the given absolute overlap.
+// A negative overlap insets the lines instead.
+// The overlap is validated using ValidateOverlap.
+func NewLinearPatternWithOverlap(lineWidth data.Micrometer, lineDistance data.Micrometer, min data.MicroPoint, max data.MicroPoint, degree int, overlap data.Micrometer) (Pattern, error) {
+	if err := ValidateOverlap(overlap, lineWidth); err != nil {
+		return nil, err
+	}
+
+	return linear{
+		lineDistance: lineDistance,
+		lineWidth:    lineWidth,
+		degree:       degree,
+		min:          min,
+		max:          max,
+		overlap:      overlap,
+	}, nil
+}
+
+// NewLinearPatternWithWallOverlap provides the same pattern as NewLinearPatternWithOverlap
+// but it can grow the lines by a separate wallOverlap into the walls using FillNextToWalls.
+// This allows to reduce gaps against the walls without over-extruding at other boundaries.
+// Both overlaps are validated using ValidateOverlap.
+func NewLinearPatternWithWallOverlap(lineWidth data.Micrometer, lineDistance data.Micrometer, min data.MicroPoint, max data.MicroPoint, degree int, overlap data.Micrometer, wallOverlap data.Micrometer) (WallAwarePattern, error) {
+	if err := ValidateOverlap(overlap, lineWidth); err != nil {
+		return nil, err
+	}
+	if err := ValidateOverlap(wallOverlap, lineWidth); err != nil {
+		return nil, err
+	}
+
+	return linear{
+		lineDistance: lineDistance,
+		lineWidth:    lineWidth,
+		degree:       degree,
+		min:          min,
+		max:          max,
+		overlap:      overlap,
+		wallOverlap:  wallOverlap,
+	}, nil
+}
+
+// NewLinearPatternOnGrid provides the same pattern as NewLinearPatternWithOverlap
+// but the lines are placed at multiples of the lineDistance from the given grid origin.
+// So the lines stack consistently across all layers and parts, independent of the size of the model.
+// The overlap is validated using ValidateOverlap.
+func NewLinearPatternOnGrid(lineWidth data.Micrometer, lineDistance data.Micrometer, origin data.MicroPoint, degree int, overlap data.Micrometer) (Pattern, error) {
+	if err := ValidateOverlap(overlap, lineWidth); err != nil {
+		return nil, err
+	}
+
+	return linear{
+		lineDistance: lineDistance,
+		lineWidth:    lineWidth,
+		degree:       degree,
+		overlap:      overlap,
+		onGrid:       true,
+		gridOrigin:   origin,
+	}, nil
+}
+
+// OverlapFromPercent converts an overlap given in percent of the line width into an absolute overlap.
+func OverlapFromPercent(lineWidth data.Micrometer, percent int) data.Micrometer {
+	return data.Micrometer(float32(lineWidth) * float32(percent) / 100.0)
+}
+
+// ValidateOverlap checks if the given absolute overlap can be used for lines of the given width.
+// The overlap may be negative to inset the lines, but its absolute value must not exceed the line width.
+func ValidateOverlap(overlap data.Micrometer, lineWidth data.Micrometer) error {
+	if overlap > lineWidth {
+		return errors.New("the overlap must not exceed the line width")
+	}
+	if overlap < -lineWidth {
+		return errors.New("a negative overlap must not inset more than the line width")
+	}
+
+	return nil
+}
+
 // Fill implements the Pattern interface by using simple linear lines as infill.
-func (p linear) Fill(layerNr int, part data.LayerPart) data.Paths {
+func (p linear) Fill(ctx context.Context, layerNr int, part data.LayerPart) (data.Paths, error) {
+	return p.fill(ctx, layerNr, part, nil)
+}
+
+// FillNextToWalls implements the WallAwarePattern interface.
+func (p linear) FillNextToWalls(ctx context.Context, layerNr int, part data.LayerPart, walls []data.LayerPart) (data.Paths, error) {
+	return p.fill(ctx, layerNr, part, walls)
+}
+
+// fill generates the lines for the part.
+// If walls are given, the lines grow by the wall overlap into them.
+func (p linear) fill(ctx context.Context, layerNr int, part data.LayerPart, walls []data.LayerPart) (data.Paths, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := validateParts([]data.LayerPart{part}); err != nil {
+		return nil, err
+	}
+	if err := validateParts(walls); err != nil {
+		return nil, err
+	}
+
 	rotation := float64(p.degree)
 
 	if layerNr%2 == 0 {
 		rotation += 90
 	}
 
-	// copy holes and outline as the original layer part should not be modified by the rotation (slices are passed by reference)
+	outline, holes := rotatedCopy(part, rotation)
+
+	var min, max data.MicroPoint
+	if p.onGrid {
+		min, max = p.gridBounds(outline, rotation)
+	} else {
+		// create rectangle for the max bounding box and rotate it,
+		// then get the min and max from the rotated bounding rectangle.
+		bounds := data.Path{
+			p.min,
+			data.NewMicroPoint(p.max.X(), p.min.Y()),
+			p.max,
+			data.NewMicroPoint(p.min.X(), p.max.Y()),
+		}
+		bounds.Rotate(rotation)
+		min, max = bounds.Bounds()
+	}
+
+	var rotatedWalls clipper.Paths
+	for _, wall := range walls {
+		wallOutline, wallHoles := rotatedCopy(wall, rotation)
+		rotatedWalls = append(rotatedWalls, clipperPath(wallOutline))
+		rotatedWalls = append(rotatedWalls, clipperPaths(wallHoles)...)
+	}
+
+	resultInfill, err := p.getInfill(min, max, clipperPath(outline), clipperPaths(holes), rotatedWalls)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := sortInfill(ctx, microPaths(resultInfill, false))
+	if err != nil {
+		return nil, err
+	}
+
+	result.Rotate(-rotation)
+
+	return result, nil
+}
+
+// gridBounds returns bounds for getInfill which cover the rotated outline (including the overlap)
+// and whose min X is on the global grid through the rotated grid origin.
+func (p linear) gridBounds(outline data.Path, rotation float64) (data.MicroPoint, data.MicroPoint) {
+	reach := p.overlap
+	if p.wallOverlap > reach {
+		reach = p.wallOverlap
+	}
+	if reach < 0 {
+		reach = 0
+	}
+
+	min, max := outline.Bounds()
+	min = min.Sub(data.NewMicroPoint(reach, reach))
+	max = max.Add(data.NewMicroPoint(reach, reach))
+
+	originX := p.gridOrigin.Rotate(rotation).X()
+	return data.NewMicroPoint(alignToGrid(min.X(), p.lineDistance, originX), min.Y()), max
+}
+
+// rotatedCopy returns a copy of the outline and the holes of the part rotated by the given degree.
+// The original layer part is not modified by the rotation (slices are passed by reference).
+func rotatedCopy(part data.LayerPart, rotation float64) (data.Path, data.Paths) {
 	var holes = data.Paths{}
 	for _, points := range part.Holes() {
 		var copied = make(data.Path, len(points))
@@ -48,33 +229,17 @@ func (p linear) Fill(layerNr int, part data.LayerPart) data.Paths {
 	var outline = make(data.Path, len(part.Outline()))
 	copy(outline, part.Outline())
 
-	// rotate them
 	outline.Rotate(rotation)
 	holes.Rotate(rotation)
 
-	// create rectangle for the max bounding box and rotate it,
-	// then get the min and max from the rotated bounding rectangle.
-	bounds := data.Path{
-		p.min,
-		data.NewMicroPoint(p.max.X(), p.min.Y()),
-		p.max,
-		data.NewMicroPoint(p.min.X(), p.max.Y()),
-	}
-	bounds.Rotate(rotation)
-	min, max := bounds.Bounds()
-
-	resultInfill := p.getInfill(min, max, clipperPath(outline), clipperPaths(holes), 0)
-	result := p.sortInfill(microPaths(resultInfill, false))
-
-	result.Rotate(-rotation)
-
-	return result
+	return outline, holes
 }
 
 // sortInfill optimizes the order of the infill lines.
-func (p linear) sortInfill(unsorted data.Paths) data.Paths {
+// If the context gets cancelled, the context error is returned.
+func sortInfill(ctx context.Context, unsorted data.Paths) (data.Paths, error) {
 	if len(unsorted) == 0 {
-		return unsorted
+		return unsorted, nil
 	}
 
 	// Save all sorted paths here.
@@ -91,6 +256,10 @@ func (p linear) sortInfill(unsorted data.Paths) data.Paths {
 	lastPoint := 0
 
 	for len(sorted) < len(unsorted) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
 		point := unsorted[lastindex][lastPoint]
 
 		bestIndex := -1
@@ -134,40 +303,51 @@ func (p linear) sortInfill(unsorted data.Paths) data.Paths {
 		panic("the sorted lines should have the same amount as the unsorted lines")
 	}
 
-	return sorted
+	return sorted, nil
 }
 
 // getInfill fills a polygon (with holes)
-func (p linear) getInfill(min data.MicroPoint, max data.MicroPoint, outline clipper.Path, holes clipper.Paths, overlap float32) clipper.Paths {
-	var result clipper.Paths
-
-	// clip the paths with the lines using intersection
-	exset := clipper.Paths{outline}
-
-	co := clipper.NewClipperOffset()
-	cl := clipper.NewClipper(clipper.IoNone)
+// The lines are placed on the grid defined by min and max,
+// but only the lines which cross the bounding box of the polygon itself are generated.
+// This avoids clipping lines across the whole model when filling many small parts.
+// The lines grow by the overlap of the pattern into the outline and the holes
+// and by its wall overlap into the walls, if any are given.
+// If clipper fails, a *ClipError is returned.
+func (p linear) getInfill(min data.MicroPoint, max data.MicroPoint, outline clipper.Path, holes clipper.Paths, walls clipper.Paths) (clipper.Paths, error) {
+	overlap := p.overlap
+	if len(walls) > 0 && p.wallOverlap > overlap {
+		overlap = p.wallOverlap
+	}
 
-	// generate the ex-set for the overlap (only if needed)
-	if overlap != 0 {
-		co.AddPaths(exset, clipper.JtSquare, clipper.EtClosedPolygon)
-		co.MiterLimit = 2
-		exset = co.Execute(float64(-overlap))
+	partMin, partMax := microPath(outline, false).Bounds()
+	if overlap > 0 {
+		partMin = partMin.Sub(data.NewMicroPoint(overlap, overlap))
+		partMax = partMax.Add(data.NewMicroPoint(overlap, overlap))
+	}
 
-		co.Clear()
-		co.AddPaths(holes, clipper.JtSquare, clipper.EtClosedPolygon)
-		co.MiterLimit = 2
-		holes = co.Execute(float64(overlap))
+	// keep the lines on the same grid as if the whole bounding box was filled
+	startX := min.X()
+	if partMin.X() > startX {
+		startX = alignToGrid(partMin.X(), p.lineDistance, min.X())
+	}
+	endX := max.X()
+	if partMax.X() < endX {
+		endX = partMax.X()
 	}
 
-	// clip the lines by the outline and holes
-	cl.AddPaths(exset, clipper.PtClip, true)
-	cl.AddPaths(holes, clipper.PtClip, true)
+	lines := verticalLines(data.NewMicroPoint(startX, partMin.Y()), data.NewMicroPoint(endX, partMax.Y()), startX, p.lineDistance)
+	if len(walls) > 0 {
+		return clipLinesNextToWalls(outline, holes, lines, p.overlap, walls, p.wallOverlap)
+	}
+	return clipLines(outline, holes, lines, p.overlap)
+}
 
-	verticalLines := clipper.Paths{}
-	numLine := 0
-	// generate the verticalLines
-	for x := min.X(); x <= max.X(); x += p.lineDistance {
-		verticalLines = append(verticalLines, clipper.Path{
+// verticalLines generates vertical lines with the given distance which cover the bounding box of min and max.
+// The first line is placed at startX.
+func verticalLines(min data.MicroPoint, max data.MicroPoint, startX data.Micrometer, lineDistance data.Micrometer) clipper.Paths {
+	lines := clipper.Paths{}
+	for x := startX; x <= max.X(); x += lineDistance {
+		lines = append(lines, clipper.Path{
 			&clipper.IntPoint{
 				X: clipper.CInt(x),
 				Y: clipper.CInt(max.Y()),
@@ -177,20 +357,116 @@ func (p linear) getInfill(min data.MicroPoint, max data.MicroPoint, outline clip
 				Y: clipper.CInt(min.Y()),
 			},
 		})
-		numLine++
 	}
 
-	cl.AddPaths(verticalLines, clipper.PtSubject, false)
+	return lines
+}
+
+// clipLines clips the given open lines by a polygon (with holes).
+// The overlap is the absolute distance the lines should grow into the outline and the holes.
+// If clipper fails, a *ClipError is returned.
+func clipLines(outline clipper.Path, holes clipper.Paths, lines clipper.Paths, overlap data.Micrometer) (clipper.Paths, error) {
+	var result clipper.Paths
+
+	// clip the paths with the lines using intersection
+	exset := clipper.Paths{outline}
+
+	cl := clipper.NewClipper(clipper.IoNone)
+
+	// generate the ex-set for the overlap (only if needed)
+	if overlap != 0 {
+		exset = squareOffset(exset, overlap)
+		holes = squareOffset(holes, -overlap)
+	}
+
+	// clip the lines by the outline and holes
+	cl.AddPaths(exset, clipper.PtClip, true)
+	cl.AddPaths(holes, clipper.PtClip, true)
+
+	cl.AddPaths(lines, clipper.PtSubject, false)
 
 	tree, ok := cl.Execute2(clipper.CtIntersection, clipper.PftEvenOdd, clipper.PftEvenOdd)
 	if !ok {
-		fmt.Println("getLinearFill failed")
-		return nil
+		return nil, newClipError(clipper.CtIntersection, exset, holes, lines)
+	}
+
+	for _, c := range tree.Childs() {
+		result = append(result, c.Contour())
+	}
+
+	return result, nil
+}
+
+// clipLinesNextToWalls clips the given open lines by a polygon (with holes) like clipLines.
+// But the lines grow by wallOverlap into the given walls and by overlap into all other boundaries.
+// All boundaries which are nearer to the walls than the larger of both overlaps are treated as wall boundaries.
+// If clipper fails, a *ClipError is returned.
+func clipLinesNextToWalls(outline clipper.Path, holes clipper.Paths, lines clipper.Paths, overlap data.Micrometer, walls clipper.Paths, wallOverlap data.Micrometer) (clipper.Paths, error) {
+	region := append(clipper.Paths{outline}, holes...)
+
+	reach := overlap
+	if reach < 0 {
+		reach = -reach
+	}
+	if wallOverlap > reach {
+		reach = wallOverlap
+	} else if -wallOverlap > reach {
+		reach = -wallOverlap
+	}
+
+	nearWalls := squareOffset(walls, reach)
+	grown := squareOffset(region, overlap)
+	grownIntoWalls := squareOffset(region, wallOverlap)
+
+	cl := clipper.NewClipper(clipper.IoNone)
+	cl.AddPaths(grown, clipper.PtSubject, true)
+	cl.AddPaths(nearWalls, clipper.PtClip, true)
+	other, ok := cl.Execute1(clipper.CtDifference, clipper.PftNonZero, clipper.PftNonZero)
+	if !ok {
+		return nil, newClipError(clipper.CtDifference, grown, nearWalls)
+	}
+
+	cl = clipper.NewClipper(clipper.IoNone)
+	cl.AddPaths(grownIntoWalls, clipper.PtSubject, true)
+	cl.AddPaths(nearWalls, clipper.PtClip, true)
+	atWalls, ok := cl.Execute1(clipper.CtIntersection, clipper.PftNonZero, clipper.PftNonZero)
+	if !ok {
+		return nil, newClipError(clipper.CtIntersection, grownIntoWalls, nearWalls)
 	}
 
+	// merge both regions, so that the lines are not split where they meet
+	cl = clipper.NewClipper(clipper.IoNone)
+	cl.AddPaths(other, clipper.PtSubject, true)
+	cl.AddPaths(atWalls, clipper.PtClip, true)
+	area, ok := cl.Execute1(clipper.CtUnion, clipper.PftNonZero, clipper.PftNonZero)
+	if !ok {
+		return nil, newClipError(clipper.CtUnion, other, atWalls)
+	}
+
+	// clip the lines by the merged region
+	cl = clipper.NewClipper(clipper.IoNone)
+	cl.AddPaths(area, clipper.PtClip, true)
+	cl.AddPaths(lines, clipper.PtSubject, false)
+
+	tree, ok := cl.Execute2(clipper.CtIntersection, clipper.PftNonZero, clipper.PftNonZero)
+	if !ok {
+		return nil, newClipError(clipper.CtIntersection, area, lines)
+	}
+
+	var result clipper.Paths
 	for _, c := range tree.Childs() {
 		result = append(result, c.Contour())
 	}
 
-	return result
+	return result, nil
+}
+
+// squareOffset offsets the polygons by the given distance using square corners like clipLines.
+// If the distance is 0, the polygons are returned unchanged.
+func squareOffset(polygons clipper.Paths, distance data.Micrometer) clipper.Paths {
+	if distance == 0 {
+		return polygons
+	}
+
+	return offsetPolygons(polygons, float64(distance), SquareJoin, options{})
 }
@@ -0,0 +1,58 @@
+// This file provides the restriction of infill to a mask region.
+
+package clip
+
+import (
+	"GoSlice/data"
+	"context"
+
+	clipper "github.com/aligator/go.clipper"
+)
+
+// FillMasked fills the part using the pattern like Pattern.Fill,
+// but only keeps the parts of the lines which are inside of the mask.
+// This allows to fill different regions of a part with different patterns in separate passes without overlap,
+// e.g. the surface regions dense and the core regions sparse.
+// The pattern is still aligned as for the whole part, the mask only cuts the lines.
+//
+// The mask polygons are combined using the EvenOdd fill type.
+// The order of the lines is kept, a line which is cut into several pieces is replaced by them.
+// If the mask is empty, the result is the same as Pattern.Fill.
+// If a coordinate exceeds MaxCoordinate, a *CoordinateError is returned.
+// If clipper fails, a *ClipError is returned.
+func FillMasked(ctx context.Context, pattern Pattern, layerNr int, part data.LayerPart, mask data.Paths) (data.Paths, error) {
+	if err := validatePaths(mask); err != nil {
+		return nil, err
+	}
+
+	paths, err := pattern.Fill(ctx, layerNr, part)
+	if err != nil || len(mask) == 0 {
+		return paths, err
+	}
+
+	clipperMask := clipperPaths(mask)
+
+	var result data.Paths
+	for _, path := range paths {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		line := clipper.Paths{clipperPath(path)}
+
+		cl := clipper.NewClipper(clipper.IoNone)
+		cl.AddPaths(clipperMask, clipper.PtClip, true)
+		cl.AddPaths(line, clipper.PtSubject, false)
+
+		tree, ok := cl.Execute2(clipper.CtIntersection, clipper.PftEvenOdd, clipper.PftEvenOdd)
+		if !ok {
+			return nil, newClipError(clipper.CtIntersection, clipperMask, line)
+		}
+
+		for _, c := range tree.Childs() {
+			result = append(result, microPath(c.Contour(), false))
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,62 @@
+// This file provides an approximation of the medial axis of layer parts.
+
+package clip
+
+import (
+	"GoSlice/data"
+
+	clipper "github.com/aligator/go.clipper"
+)
+
+// MedialAxis approximates the medial axis (the skeleton) of the part.
+// It returns open polylines along the center of the part, which can be used e.g. for thin walls,
+// variable width walls or to fill gaps.
+// The center of a closed band (e.g. a ring) is returned as a closed loop, which repeats its first point at the end.
+//
+// The part is offset inwards by the resolution again and again until it vanishes.
+// At each step the regions which collapse are detected and their center lines are added to the result.
+// A smaller resolution gives a more exact result but needs more offset operations.
+//
+// If the resolution is <= 0, nil is returned.
+// If a coordinate exceeds MaxCoordinate, a *CoordinateError is returned.
+// If clipper fails, a *ClipError is returned.
+func MedialAxis(part data.LayerPart, resolution data.Micrometer) (data.Paths, error) {
+	if err := validateParts([]data.LayerPart{part}); err != nil {
+		return nil, err
+	}
+
+	if resolution <= 0 {
+		return nil, nil
+	}
+
+	c := clipperClipper{}
+
+	var result data.Paths
+	current := []data.LayerPart{part}
+	for len(current) > 0 {
+		next := miterOffsetParts(current, -resolution)
+
+		// the regions of the current which do not survive the next offset collapse at this step
+		reopened := miterOffsetParts(next, resolution)
+		collapsed := current
+		if len(reopened) > 0 {
+			var ok bool
+			collapsed, ok = c.Difference(current, reopened)
+			if !ok {
+				return nil, newClipError(clipper.CtDifference, partsToClipperPaths(current), partsToClipperPaths(reopened))
+			}
+		}
+
+		for _, region := range collapsed {
+			lines, err := centerLine(region, 2*resolution, 2*resolution)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, lines...)
+		}
+
+		current = next
+	}
+
+	return result, nil
+}
@@ -0,0 +1,178 @@
+// This file provides the merging of collinear line fragments to reduce retractions.
+
+package clip
+
+import (
+	"GoSlice/data"
+	"context"
+	"math"
+	"sort"
+)
+
+// merged wraps a pattern and merges collinear fragments of its lines which are separated only by a small gap.
+type merged struct {
+	pattern Pattern
+	maxGap  data.Micrometer
+}
+
+// NewMergedPattern wraps the given pattern and merges collinear line fragments whose ends are at most maxGap apart.
+// Clipper splits a line at every boundary it crosses, also at slivers which are thinner than any printable feature.
+// Printing the fragments separately results in needless retractions.
+//
+// The maxGap should be smaller than the smallest feature (e.g. the line width),
+// so that a line never bridges a real hole, only negligible gaps.
+// Only straight lines consisting of two points are merged, all other paths are kept as they are.
+// The merged line replaces its first fragment, so the order of the lines is kept.
+// If maxGap is <= 0, the lines are not changed.
+func NewMergedPattern(pattern Pattern, maxGap data.Micrometer) Pattern {
+	return merged{
+		pattern: pattern,
+		maxGap:  maxGap,
+	}
+}
+
+// Fill implements the Pattern interface by merging the lines of the wrapped pattern.
+func (p merged) Fill(ctx context.Context, layerNr int, part data.LayerPart) (data.Paths, error) {
+	paths, err := p.pattern.Fill(ctx, layerNr, part)
+	if err != nil || p.maxGap <= 0 {
+		return paths, err
+	}
+
+	return mergeCollinear(paths, p.maxGap), nil
+}
+
+// fragment is a straight line described along its carrier line.
+type fragment struct {
+	index    int
+	angle    float64
+	offset   float64
+	start    float64
+	end      float64
+	reversed bool
+}
+
+// collinearTolerance is the distance in micrometers up to which fragments are treated as being on the same line.
+// It covers the rounding of rotated lines.
+const collinearTolerance = 2
+
+// mergeCollinear merges all straight lines which are on the same carrier line and at most maxGap apart.
+func mergeCollinear(paths data.Paths, maxGap data.Micrometer) data.Paths {
+	var fragments []fragment
+	for i, path := range paths {
+		if len(path) != 2 || (path[0].X() == path[1].X() && path[0].Y() == path[1].Y()) {
+			continue
+		}
+		fragments = append(fragments, newFragment(i, path))
+	}
+
+	sort.SliceStable(fragments, func(i, j int) bool {
+		if fragments[i].angle != fragments[j].angle {
+			return fragments[i].angle < fragments[j].angle
+		}
+		return fragments[i].offset < fragments[j].offset
+	})
+
+	// the fragments which are merged into another one are removed and the first fragment of each group is extended
+	removed := make([]bool, len(paths))
+	extended := map[int]data.Path{}
+
+	for groupStart := 0; groupStart < len(fragments); {
+		groupEnd := groupStart + 1
+		for groupEnd < len(fragments) &&
+			fragments[groupEnd].angle-fragments[groupEnd-1].angle < 1e-6 &&
+			fragments[groupEnd].offset-fragments[groupEnd-1].offset <= collinearTolerance {
+			groupEnd++
+		}
+
+		mergeGroup(paths, fragments[groupStart:groupEnd], maxGap, removed, extended)
+		groupStart = groupEnd
+	}
+
+	var result data.Paths
+	for i, path := range paths {
+		if removed[i] {
+			continue
+		}
+		if line, ok := extended[i]; ok {
+			path = line
+		}
+		result = append(result, path)
+	}
+
+	return result
+}
+
+// newFragment describes the line by the canonical angle of its direction (in [0, Pi)),
+// its perpendicular offset from the origin and its start and end along the direction.
+func newFragment(index int, line data.Path) fragment {
+	a, b := line[0], line[1]
+	reversed := false
+
+	angle := math.Atan2(float64(b.Y()-a.Y()), float64(b.X()-a.X()))
+	if angle < 0 || angle >= math.Pi {
+		angle -= math.Copysign(math.Pi, angle)
+		a, b = b, a
+		reversed = true
+	}
+	// round the angle, so that rounded coordinates of parallel lines result in the same angle
+	angle = math.Round(angle*1e4) / 1e4
+
+	dx, dy := math.Cos(angle), math.Sin(angle)
+	return fragment{
+		index:    index,
+		angle:    angle,
+		offset:   dx*float64(a.Y()) - dy*float64(a.X()),
+		start:    dx*float64(a.X()) + dy*float64(a.Y()),
+		end:      dx*float64(b.X()) + dy*float64(b.Y()),
+		reversed: reversed,
+	}
+}
+
+// mergeGroup merges the fragments of one carrier line which are at most maxGap apart.
+func mergeGroup(paths data.Paths, group []fragment, maxGap data.Micrometer, removed []bool, extended map[int]data.Path) {
+	sort.SliceStable(group, func(i, j int) bool {
+		return group[i].start < group[j].start
+	})
+
+	first := group[0]
+	end := first.end
+	for _, f := range group[1:] {
+		if f.start-end <= float64(maxGap) {
+			// merge the fragment into the current line
+			removed[f.index] = true
+			if f.end > end {
+				end = f.end
+				first = extend(paths, first, f, extended)
+			}
+			continue
+		}
+
+		first = f
+		end = f.end
+	}
+}
+
+// extend changes the line of the first fragment so that it ends at the end of the other fragment.
+// The direction of the first line is kept.
+func extend(paths data.Paths, first fragment, other fragment, extended map[int]data.Path) fragment {
+	line, ok := extended[first.index]
+	if !ok {
+		line = data.Path{paths[first.index][0], paths[first.index][1]}
+	}
+
+	otherLine := paths[other.index]
+	otherEnd := otherLine[1]
+	if other.reversed {
+		otherEnd = otherLine[0]
+	}
+
+	if first.reversed {
+		line[0] = otherEnd
+	} else {
+		line[1] = otherEnd
+	}
+	extended[first.index] = line
+
+	first.end = other.end
+	return first
+}
@@ -0,0 +1,72 @@
+// This file provides the offset primitive which is used by all offset operations.
+
+package clip
+
+import (
+	"GoSlice/data"
+
+	clipper "github.com/aligator/go.clipper"
+)
+
+// JoinType defines how the corners are joined when offsetting polygons.
+// It maps directly to the join types of the external clipper lib.
+type JoinType int
+
+const (
+	// SquareJoin cuts off convex corners at the offset distance.
+	// It is used by the infill overlap and by Inset, unless another join type is set by WithWallJoin.
+	SquareJoin JoinType = iota
+
+	// RoundJoin rounds convex corners.
+	// The precision can be set by the WithArcTolerance option.
+	RoundJoin
+
+	// MiterJoin keeps convex corners sharp, up to two times the offset distance.
+	MiterJoin
+)
+
+// clipperJoinType converts the join type to the representation which is used by the external clipper lib.
+func (j JoinType) clipperJoinType() clipper.JoinType {
+	switch j {
+	case RoundJoin:
+		return clipper.JtRound
+	case MiterJoin:
+		return clipper.JtMiter
+	default:
+		return clipper.JtSquare
+	}
+}
+
+// newClipperOffset prepares a clipper offset for the closed polygons using the given join type and options.
+// It is only used by offsetPolygons and offsetToParts, which all offsets of this package go through.
+func newClipperOffset(polygons clipper.Paths, join JoinType, o options) *clipper.ClipperOffset {
+	co := clipper.NewClipperOffset()
+	co.AddPaths(polygons, join.clipperJoinType(), clipper.EtClosedPolygon)
+	co.MiterLimit = 2
+	o.applyArcTolerance(co)
+
+	return co
+}
+
+// offsetPolygons offsets the closed polygons by the signed distance and returns the resulting polygons.
+func offsetPolygons(polygons clipper.Paths, distance float64, join JoinType, o options) clipper.Paths {
+	return newClipperOffset(polygons, join, o).Execute(distance)
+}
+
+// offsetToParts offsets the closed polygons by the signed distance
+// and combines the resulting polygons into layer parts.
+func offsetToParts(polygons clipper.Paths, distance float64, join JoinType, o options) []data.LayerPart {
+	return polyTreeToLayerParts(newClipperOffset(polygons, join, o).Execute2(distance))
+}
+
+func (c clipperClipper) Offset(paths data.Paths, distance data.Micrometer, join JoinType, opts ...Option) (data.Paths, error) {
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	if err := validatePaths(paths); err != nil {
+		return nil, err
+	}
+
+	return microPaths(offsetPolygons(clipperPaths(paths), float64(distance), join, newOptions(opts...)), false), nil
+}
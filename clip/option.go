@@ -0,0 +1,193 @@
+// This file provides options which can be passed to some clip operations.
+
+package clip
+
+import (
+	"GoSlice/data"
+
+	clipper "github.com/aligator/go.clipper"
+)
+
+// FillType defines which regions of overlapping or self-intersecting polygons are counted as filled.
+// It maps directly to the fill types of the external clipper lib.
+type FillType int
+
+const (
+	// EvenOdd fills all regions with an odd winding number.
+	// It does not depend on the orientation of the polygons.
+	EvenOdd FillType = iota
+
+	// NonZero fills all regions with a winding number which is not zero.
+	// This is the right rule for correctly wound contours (outlines counter clockwise, holes clockwise).
+	NonZero
+
+	// Positive fills all regions with a winding number > 0.
+	Positive
+
+	// Negative fills all regions with a winding number < 0.
+	Negative
+)
+
+// clipperFillType converts the fill type to the representation which is used by the external clipper lib.
+func (f FillType) clipperFillType() clipper.PolyFillType {
+	switch f {
+	case NonZero:
+		return clipper.PftNonZero
+	case Positive:
+		return clipper.PftPositive
+	case Negative:
+		return clipper.PftNegative
+	default:
+		return clipper.PftEvenOdd
+	}
+}
+
+// options contains the settings which can be changed by passing an Option.
+type options struct {
+	// fillType is the fill type used for the main clipper operation.
+	fillType FillType
+
+	// cleanup enables resolving self-intersections of each input polygon before the union.
+	cleanup         bool
+	cleanupFillType FillType
+
+	// expansion is the signed distance all walls are moved outwards.
+	expansion data.Micrometer
+
+	// pointFilter replaces the default simplification of the input polygons by data.Path.FilterNearPoints.
+	pointFilter          bool
+	pointFilterDistance  data.Micrometer
+	pointFilterDeviation data.Micrometer
+
+	// arcTolerance is the max distance between the exact arc and its approximation for round joins.
+	// If it is 0, the default of the external clipper lib is used.
+	arcTolerance data.Micrometer
+
+	// wallJoin is the join type of the wall offsets.
+	wallJoin JoinType
+
+	// incrementalInset calculates each wall from the previous wall instead of the original outline.
+	incrementalInset bool
+
+	// thinRingCenterLine replaces rings which are too thin for their walls by a loop in the middle of the band.
+	thinRingCenterLine bool
+
+	// stats is filled by GenerateLayerParts if it is set.
+	stats *LayerPartsStats
+
+	// nestingDepth adds the nesting depth to the attributes of the generated parts.
+	nestingDepth bool
+}
+
+// Option can be passed to some clip operations to change their behaviour.
+type Option func(o *options)
+
+// newOptions returns the default options with all given options applied.
+func newOptions(opts ...Option) options {
+	o := options{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithFillType sets the fill type which is used to decide which regions of the input polygons are filled.
+// By default EvenOdd is used.
+//
+// It is used by GenerateLayerParts, Difference, Intersection and Union.
+func WithFillType(fillType FillType) Option {
+	return func(o *options) {
+		o.fillType = fillType
+	}
+}
+
+// WithCleanup resolves self-intersections of each input polygon individually, using the given fill type,
+// before all polygons are combined.
+// The orientation of the polygons is kept, so holes stay holes.
+//
+// It is used by GenerateLayerParts.
+func WithCleanup(fillType FillType) Option {
+	return func(o *options) {
+		o.cleanup = true
+		o.cleanupFillType = fillType
+	}
+}
+
+// WithExpansion grows (positive value) or shrinks (negative value) the outline of a part by the given amount
+// before the walls are calculated.
+// All walls are moved by the same amount, so the inner walls keep their distance to the adjusted outer wall.
+// This can be used for horizontal expansion or elephant foot compensation.
+//
+// It is used by Inset and InsetLayer.
+func WithExpansion(expansion data.Micrometer) Option {
+	return func(o *options) {
+		o.expansion = expansion
+	}
+}
+
+// WithPointFilter replaces the default simplification of the input polygons
+// by a filter which removes points that are nearer than minDistance to the last kept point,
+// as long as the shape does not deviate more than maxDeviation (see data.Path.FilterNearPoints).
+// This preserves finely sampled curves which may lose their shape by the default simplification.
+//
+// It is used by GenerateLayerParts.
+func WithPointFilter(minDistance data.Micrometer, maxDeviation data.Micrometer) Option {
+	return func(o *options) {
+		o.pointFilter = true
+		o.pointFilterDistance = minDistance
+		o.pointFilterDeviation = maxDeviation
+	}
+}
+
+// WithArcTolerance sets the max distance (in micrometer) between an exact arc and the path
+// which approximates it when offsetting with round joins.
+// A lower tolerance results in more points and smoother arcs.
+// By default the value of the external clipper lib is used.
+// The tolerance only has an effect on RoundJoin, so the walls are only affected if WithWallJoin(RoundJoin) is set.
+//
+// It is used by Offset, Inset, InsetLayer and InsetWithOrigin.
+func WithArcTolerance(tolerance data.Micrometer) Option {
+	return func(o *options) {
+		o.arcTolerance = tolerance
+	}
+}
+
+// WithWallJoin sets how the corners of the walls are joined.
+// By default SquareJoin is used. RoundJoin gives rounded outer corners, whose precision can be set by WithArcTolerance.
+//
+// It is used by Inset, InsetLayer and InsetWithOrigin.
+func WithWallJoin(join JoinType) Option {
+	return func(o *options) {
+		o.wallJoin = join
+	}
+}
+
+// applyArcTolerance sets the arc tolerance on the given clipper offset if it is configured.
+func (o options) applyArcTolerance(co *clipper.ClipperOffset) {
+	if o.arcTolerance > 0 {
+		co.ArcTolerance = float64(o.arcTolerance)
+	}
+}
+
+// WithIncrementalInset calculates each wall by offsetting the previous wall
+// instead of offsetting the original outline by a growing distance.
+// This keeps the distance between the walls the same even through tight curves and corners.
+// It needs more calculations and may behave differently near features which collapse.
+//
+// It is used by Inset, InsetLayer and InsetWithOrigin.
+func WithIncrementalInset() Option {
+	return func(o *options) {
+		o.incrementalInset = true
+	}
+}
+
+// WithThinRingCenterLine prints a single loop in the middle of rings which are too thin for their walls.
+// Without it, the walls of such a ring stop once the band gets thinner than one wall,
+// but the outer walls are kept even if they overlap.
+//
+// It is used by Inset, InsetLayer and InsetWithOrigin.
+func WithThinRingCenterLine() Option {
+	return func(o *options) {
+		o.thinRingCenterLine = true
+	}
+}
@@ -0,0 +1,110 @@
+// This file provides the tracking of the origin of inset walls.
+
+package clip
+
+import (
+	"GoSlice/data"
+	"math"
+)
+
+// Origin identifies the path of a layer part a wall was created from.
+// Holes are identified by their index in data.LayerPart.Holes().
+type Origin int
+
+// OutlineOrigin is the origin of all walls created from the outline of a part.
+const OutlineOrigin Origin = -1
+
+// IsHole returns true if the origin is a hole of the part.
+func (o Origin) IsHole() bool {
+	return o != OutlineOrigin
+}
+
+// OriginPaths contains walls which were created from the same path of a layer part.
+type OriginPaths struct {
+	// Origin is the path of the original layer part the walls were created from.
+	Origin Origin
+
+	// Paths are the closed walls.
+	Paths data.Paths
+}
+
+func (c clipperClipper) InsetWithOrigin(part data.LayerPart, offset data.Micrometer, insetCount int, opts ...Option) [][]OriginPaths {
+	var result [][]OriginPaths
+	for _, insetParts := range c.Inset(part, offset, insetCount, opts...) {
+		var walls data.Paths
+		for _, insetPart := range insetParts {
+			walls = append(walls, insetPart.Outline())
+			walls = append(walls, insetPart.Holes()...)
+		}
+
+		result = append(result, groupByOrigin(part, walls))
+	}
+
+	return result
+}
+
+// groupByOrigin assigns each wall to the path of the part which is nearest to it.
+// If walls of the outline and a hole merged into one wall, the nearest path to its first point is used.
+func groupByOrigin(part data.LayerPart, walls data.Paths) []OriginPaths {
+	groups := make([]data.Paths, len(part.Holes())+1)
+
+	for _, wall := range walls {
+		if len(wall) == 0 {
+			continue
+		}
+
+		origin := OutlineOrigin
+		nearest := distanceToLoop(wall[0], part.Outline())
+		for i, hole := range part.Holes() {
+			if d := distanceToLoop(wall[0], hole); d < nearest {
+				nearest = d
+				origin = Origin(i)
+			}
+		}
+
+		groups[origin+1] = append(groups[origin+1], wall)
+	}
+
+	var result []OriginPaths
+	for i, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+
+		result = append(result, OriginPaths{
+			Origin: Origin(i - 1),
+			Paths:  group,
+		})
+	}
+
+	return result
+}
+
+// distanceToLoop returns the distance of the point to the nearest segment of the closed loop.
+func distanceToLoop(point data.MicroPoint, loop data.Path) float64 {
+	nearest := math.Inf(1)
+	for i := range loop {
+		a := loop[i]
+		b := loop[(i+1)%len(loop)]
+
+		if d := distanceToSegment(point, a, b); d < nearest {
+			nearest = d
+		}
+	}
+
+	return nearest
+}
+
+// distanceToSegment returns the distance of the point to the line segment from a to b.
+func distanceToSegment(point, a, b data.MicroPoint) float64 {
+	abX, abY := float64(b.X()-a.X()), float64(b.Y()-a.Y())
+	apX, apY := float64(point.X()-a.X()), float64(point.Y()-a.Y())
+
+	length2 := abX*abX + abY*abY
+	t := 0.0
+	if length2 > 0 {
+		t = math.Max(0, math.Min(1, (apX*abX+apY*abY)/length2))
+	}
+
+	return math.Hypot(apX-t*abX, apY-t*abY)
+}
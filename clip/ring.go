@@ -0,0 +1,118 @@
+// This file provides the detection of ring shaped walls which are too thin to be printed.
+
+package clip
+
+import (
+	"GoSlice/data"
+	"math"
+)
+
+// isThinRing reports if the inset part is a ring whose band is narrower than one wall.
+// The walls at the outline and at the holes of such a ring would overlap each other,
+// and insetting it any further lets the hole meet the outline, which results in crossed or missing walls.
+//
+// The inset part is the region between the centers of the walls,
+// so the walls fit only if it is at least one wall (offset) wide.
+// The mean width of the band is used, so rings which are only thin at one side keep their walls.
+func isThinRing(insetPart data.LayerPart, offset data.Micrometer) bool {
+	if offset <= 0 || len(insetPart.Holes()) == 0 {
+		return false
+	}
+
+	return bandWidth(insetPart) < float64(offset)
+}
+
+// splitThinRings separates the thin rings (see isThinRing) from the inset parts.
+// Inset parts which lie inside of a thin ring of a previous inset are dropped,
+// so no further walls are generated for a ring once it got too thin.
+func splitThinRings(insetParts []data.LayerPart, offset data.Micrometer, previous []data.LayerPart) (kept []data.LayerPart, thin []data.LayerPart) {
+	for _, insetPart := range insetParts {
+		switch {
+		case insideAny(insetPart, previous):
+		case isThinRing(insetPart, offset):
+			thin = append(thin, insetPart)
+		default:
+			kept = append(kept, insetPart)
+		}
+	}
+
+	return kept, thin
+}
+
+// insideAny returns true if the first point of the outline of the part lies inside of one of the regions.
+func insideAny(part data.LayerPart, regions []data.LayerPart) bool {
+	if len(part.Outline()) == 0 {
+		return false
+	}
+
+	point := clipperPoint(part.Outline()[0])
+	for _, region := range regions {
+		if insidePart(point, clipperPath(region.Outline()), clipperPaths(region.Holes())) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ringCenterLine returns the closed loop in the middle of the band of a thin ring.
+// The outline is sampled at least every step and each sample is moved halfway to the nearest point of the holes,
+// so the loop follows the middle of the band even if the holes are not centered.
+func ringCenterLine(ring data.LayerPart, step data.Micrometer) data.Path {
+	if len(ring.Outline()) < 3 || len(ring.Holes()) == 0 {
+		return nil
+	}
+
+	var holes data.Paths
+	for _, hole := range ring.Holes() {
+		holes = append(holes, closedLoop(hole))
+	}
+
+	var result data.Path
+	for _, point := range sampleLoop(ring.Outline(), step) {
+		nearest := nearestOnLines(holes, point)
+		result = append(result, data.NewMicroPoint((point.X()+nearest.X())/2, (point.Y()+nearest.Y())/2))
+	}
+
+	return result.RemoveCollinear()
+}
+
+// sampleLoop returns the points of the closed loop with additional points on all edges which are longer than step.
+func sampleLoop(loop data.Path, step data.Micrometer) data.Path {
+	if step <= 0 {
+		return loop
+	}
+
+	var result data.Path
+	for i, a := range loop {
+		b := loop[(i+1)%len(loop)]
+		edge := b.Sub(a)
+		count := int(math.Ceil(math.Hypot(float64(edge.X()), float64(edge.Y())) / float64(step)))
+
+		result = append(result, a)
+		for j := 1; j < count; j++ {
+			t := float64(j) / float64(count)
+			result = append(result, data.NewMicroPoint(
+				a.X()+data.Micrometer(math.Round(t*float64(edge.X()))),
+				a.Y()+data.Micrometer(math.Round(t*float64(edge.Y()))),
+			))
+		}
+	}
+
+	return result
+}
+
+// bandWidth estimates the mean width of a band (a ring or a long strip) by its area and its perimeter.
+// The area is the width times the mean of the lengths of both sides of the band,
+// so the estimation is exact for a ring of the same width all around.
+func bandWidth(band data.LayerPart) float64 {
+	perimeter := float64(band.Outline().Length(true))
+	for _, hole := range band.Holes() {
+		perimeter += float64(hole.Length(true))
+	}
+	if perimeter == 0 {
+		return 0
+	}
+
+	return 2 * partsArea([]data.LayerPart{band}) / perimeter
+}
@@ -0,0 +1,31 @@
+// This file provides statistics about the geometry which is discarded by clip operations.
+
+package clip
+
+// LayerPartsStats describes how much of the input geometry was kept by GenerateLayerParts.
+// It can be used to warn if a layer lost significant detail.
+type LayerPartsStats struct {
+	// InputPolygons is the number of polygons of the layer.
+	InputPolygons int
+
+	// OutputParts is the number of resulting layer parts.
+	OutputParts int
+
+	// FilteredPoints is the number of points removed by the simplification or the point filter.
+	FilteredPoints int
+
+	// DegeneratePolygons is the number of polygons which have less than 3 points after the simplification.
+	// They have no area and are dropped by the union.
+	DegeneratePolygons int
+}
+
+// WithStats fills the given stats while generating the layer parts.
+// The stats are reset before, so the same instance can be reused for several layers.
+// It does not change the result.
+//
+// It is used by GenerateLayerParts.
+func WithStats(stats *LayerPartsStats) Option {
+	return func(o *options) {
+		o.stats = stats
+	}
+}
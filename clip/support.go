@@ -0,0 +1,197 @@
+// This file provides the separation of support areas into interface and body regions.
+
+package clip
+
+import (
+	"GoSlice/data"
+
+	clipper "github.com/aligator/go.clipper"
+)
+
+// SplitSupportInterface splits the support area of a layer into the interface regions,
+// which are directly below the model, and the body regions, which are the rest of the support.
+// The interface regions can then be filled more densely to get a clean surface below the model,
+// while the body regions can be filled sparsely to save material.
+//
+// The above paths contain the model polygons of the layers above the support layer,
+// starting with the layer directly above it.
+// Only the first interfaceLayers layers are used, so a support area is part of the interface
+// if the model is at most interfaceLayers layers above it.
+// If interfaceLayers is <= 0, the whole support area is returned as body.
+//
+// If a coordinate exceeds MaxCoordinate, a *CoordinateError is returned.
+// If clipper fails, a *ClipError is returned.
+func SplitSupportInterface(support data.Paths, above []data.Paths, interfaceLayers int) (interfaceParts []data.LayerPart, bodyParts []data.LayerPart, err error) {
+	if err := validatePaths(support); err != nil {
+		return nil, nil, err
+	}
+	for _, layer := range above {
+		if err := validatePaths(layer); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	supportParts, err := pathsToParts(clipperPaths(support))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if interfaceLayers > len(above) {
+		interfaceLayers = len(above)
+	}
+
+	if interfaceLayers <= 0 || len(supportParts) == 0 {
+		return nil, supportParts, nil
+	}
+
+	// Union each layer on its own, as the polygons of one layer
+	// are only valid together with the holes of the same layer.
+	var model []data.LayerPart
+	for _, layer := range above[:interfaceLayers] {
+		layerParts, err := pathsToParts(clipperPaths(layer))
+		if err != nil {
+			return nil, nil, err
+		}
+		model = append(model, layerParts...)
+	}
+
+	if len(model) == 0 {
+		return nil, supportParts, nil
+	}
+
+	// The same model area is usually contained in several layers,
+	// so it has to be merged by NonZero as EvenOdd would cancel out overlaps of an even number of layers.
+	c := clipperClipper{}
+	merged, ok := c.Union(model, nil, WithFillType(NonZero))
+	if !ok {
+		return nil, nil, newClipError(clipper.CtUnion, partsToClipperPaths(model))
+	}
+	model = merged
+
+	interfaceParts, ok = c.Intersection(supportParts, model)
+	if !ok {
+		return nil, nil, newClipError(clipper.CtIntersection, partsToClipperPaths(supportParts), partsToClipperPaths(model))
+	}
+
+	bodyParts, ok = c.Difference(supportParts, model)
+	if !ok {
+		return nil, nil, newClipError(clipper.CtDifference, partsToClipperPaths(supportParts), partsToClipperPaths(model))
+	}
+
+	return interfaceParts, bodyParts, nil
+}
+
+// pathsToParts combines the given polygons of one layer into layer parts using the EvenOdd fill type.
+func pathsToParts(polygons clipper.Paths) ([]data.LayerPart, error) {
+	if len(polygons) == 0 {
+		return nil, nil
+	}
+
+	cl := clipper.NewClipper(clipper.IoNone)
+	cl.AddPaths(polygons, clipper.PtSubject, true)
+	tree, ok := cl.Execute2(clipper.CtUnion, clipper.PftEvenOdd, clipper.PftEvenOdd)
+	if !ok {
+		return nil, newClipError(clipper.CtUnion, polygons)
+	}
+
+	return polyTreeToLayerParts(tree), nil
+}
+
+// partsToClipperPaths converts the outlines and holes of all given parts
+// to the representation which is used by the external clipper lib.
+func partsToClipperPaths(parts []data.LayerPart) clipper.Paths {
+	var result clipper.Paths
+	for _, part := range parts {
+		result = append(result, clipperPath(part.Outline()))
+		result = append(result, clipperPaths(part.Holes())...)
+	}
+
+	return result
+}
+
+// SupportPillars generates support as a grid of small square columns instead of filling the whole support area.
+// Such support uses less material and is easier to remove.
+//
+// A column is placed at each intersection of the grid with the given pitch which is inside of the support area.
+// The grid is aligned to the origin, so the columns of all layers stack on each other.
+// Each column is a square with the given size, centered on the intersection.
+// The columns are clipped by the support area, so they don't poke outside of it near its edges.
+//
+// The result contains the closed outlines of all columns.
+// If pitch or size is <= 0, nil is returned.
+// If a coordinate exceeds MaxCoordinate, a *CoordinateError is returned.
+// If clipper fails, a *ClipError is returned.
+func SupportPillars(support data.Paths, pitch data.Micrometer, size data.Micrometer) (data.Paths, error) {
+	if pitch <= 0 || size <= 0 || len(support) == 0 {
+		return nil, nil
+	}
+	if err := validatePaths(support); err != nil {
+		return nil, err
+	}
+
+	supportParts, err := pathsToParts(clipperPaths(support))
+	if err != nil {
+		return nil, err
+	}
+
+	var columns clipper.Paths
+	for _, part := range supportParts {
+		min, max := part.Outline().Bounds()
+		outline := clipperPath(part.Outline())
+		holes := clipperPaths(part.Holes())
+
+		for x := alignToGrid(min.X(), pitch, 0); x <= max.X(); x += pitch {
+			for y := alignToGrid(min.Y(), pitch, 0); y <= max.Y(); y += pitch {
+				center := &clipper.IntPoint{X: clipper.CInt(x), Y: clipper.CInt(y)}
+				if !insidePart(center, outline, holes) {
+					continue
+				}
+
+				columns = append(columns, clipperPath(squareAround(x, y, size)))
+			}
+		}
+	}
+
+	if len(columns) == 0 {
+		return nil, nil
+	}
+
+	area := partsToClipperPaths(supportParts)
+
+	cl := clipper.NewClipper(clipper.IoNone)
+	cl.AddPaths(columns, clipper.PtSubject, true)
+	cl.AddPaths(area, clipper.PtClip, true)
+	clipped, ok := cl.Execute1(clipper.CtIntersection, clipper.PftNonZero, clipper.PftEvenOdd)
+	if !ok {
+		return nil, newClipError(clipper.CtIntersection, columns, area)
+	}
+
+	return microPaths(clipped, false), nil
+}
+
+// insidePart returns true if the point is inside of the outline but not inside of one of the holes.
+// Points on the outline count as outside.
+func insidePart(point *clipper.IntPoint, outline clipper.Path, holes clipper.Paths) bool {
+	if clipper.PointInPolygon(point, outline) != 1 {
+		return false
+	}
+
+	for _, hole := range holes {
+		if clipper.PointInPolygon(point, hole) != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// squareAround returns a counter clockwise square with the given size, centered on x and y.
+func squareAround(x data.Micrometer, y data.Micrometer, size data.Micrometer) data.Path {
+	half := size / 2
+	return data.Path{
+		data.NewMicroPoint(x-half, y-half),
+		data.NewMicroPoint(x-half+size, y-half),
+		data.NewMicroPoint(x-half+size, y-half+size),
+		data.NewMicroPoint(x-half, y-half+size),
+	}
+}
@@ -0,0 +1,257 @@
+// This file provides the detection of thin walls which are printed as a single center line.
+
+package clip
+
+import (
+	"GoSlice/data"
+	"math"
+	"sort"
+
+	clipper "github.com/aligator/go.clipper"
+)
+
+// ThinWalls separates the regions of the part which are narrower than the threshold.
+// Insetting such regions results either in overlapping walls or in no walls at all,
+// so they should be printed as a single center line instead.
+//
+// It returns the remaining thick regions, which can be inset as usual and keep the extruder of the part,
+// and the center lines of the thin regions as open paths.
+// The threshold is usually two line widths.
+// Thin regions whose center line is shorter than one line width are dropped,
+// as well as regions which are narrower than a quarter line width, e.g. slivers along curved outlines.
+//
+// If the threshold is <= 0, the part is returned unchanged.
+// If a coordinate exceeds MaxCoordinate, a *CoordinateError is returned.
+// If clipper fails, a *ClipError is returned.
+func ThinWalls(part data.LayerPart, lineWidth data.Micrometer, threshold data.Micrometer) (thick []data.LayerPart, centerLines data.Paths, err error) {
+	if err := validateParts([]data.LayerPart{part}); err != nil {
+		return nil, nil, err
+	}
+	if threshold <= 0 || lineWidth <= 0 {
+		return []data.LayerPart{part}, nil, nil
+	}
+
+	// Everything which vanishes when offsetting inwards by half the threshold
+	// does not come back when offsetting by the same amount outwards again.
+	eroded := miterOffsetParts([]data.LayerPart{part}, -threshold/2)
+	opened := miterOffsetParts(eroded, threshold/2)
+
+	c := clipperClipper{}
+	thick, ok := c.Intersection(opened, []data.LayerPart{part})
+	if !ok {
+		return nil, nil, newClipError(clipper.CtIntersection, partsToClipperPaths(opened), partsToClipperPaths([]data.LayerPart{part}))
+	}
+
+	// the thick regions are printed by the same extruder as the part itself
+	for _, thickPart := range thick {
+		thickPart.SetExtruder(part.Extruder())
+	}
+
+	thin, ok := c.Difference([]data.LayerPart{part}, thick)
+	if !ok {
+		return nil, nil, newClipError(clipper.CtDifference, partsToClipperPaths([]data.LayerPart{part}), partsToClipperPaths(thick))
+	}
+
+	for _, region := range thin {
+		// Along curved outlines the opening leaves slivers of rounding errors, which are no thin walls.
+		if bandWidth(region) < float64(lineWidth)/4 {
+			continue
+		}
+
+		lines, err := centerLine(region, lineWidth, threshold)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		for _, line := range lines {
+			if line.Length(false) >= lineWidth {
+				centerLines = append(centerLines, line)
+			}
+		}
+	}
+
+	return thick, centerLines, nil
+}
+
+// miterOffsetParts offsets the parts like offsetParts but keeps the corners sharp,
+// so that offsetting inwards and outwards again results in the original shape.
+func miterOffsetParts(parts []data.LayerPart, distance data.Micrometer) []data.LayerPart {
+	if len(parts) == 0 {
+		return nil
+	}
+
+	return offsetToParts(partsToClipperPaths(parts), float64(distance), MiterJoin, options{})
+}
+
+// centerLine approximates the center line of a thin region.
+//
+// A closed band (a region with holes) results in one closed loop in the middle of the band (see ringCenterLine),
+// which repeats its first point at the end.
+// Other regions are cut by scanlines in two perpendicular directions, so that bands in any direction are found.
+// Only the cuts across the band are used, which are the cuts that are shorter than the perpendicular cut
+// through their middle point. The middle points of these cuts are connected to lines.
+func centerLine(region data.LayerPart, lineWidth data.Micrometer, threshold data.Micrometer) (data.Paths, error) {
+	step := lineWidth / 2
+	if step <= 0 {
+		step = 1
+	}
+
+	if len(region.Holes()) > 0 {
+		if loop := ringCenterLine(region, step); len(loop) >= 3 {
+			return data.Paths{closedLoop(loop)}, nil
+		}
+	}
+
+	vertical, err := scanCuts(region, 0, step)
+	if err != nil {
+		return nil, err
+	}
+	horizontal, err := scanCuts(region, 90, step)
+	if err != nil {
+		return nil, err
+	}
+
+	var result data.Paths
+	for _, family := range []struct {
+		rotation, otherRotation float64
+		cuts, others            []scanCut
+		keepEqual               bool
+	}{
+		{rotation: 0, otherRotation: 90, cuts: vertical, others: horizontal, keepEqual: true},
+		{rotation: 90, otherRotation: 0, cuts: horizontal, others: vertical, keepEqual: false},
+	} {
+		// collect the middle points of the cuts across the band
+		var middles data.Path
+		for _, cut := range family.cuts {
+			middle := data.NewMicroPoint(cut.x, (cut.bottom+cut.top)/2)
+
+			// the perpendicular cut in the frame of the other family
+			across := crossingLength(family.others, middle.Rotate(-family.rotation).Rotate(family.otherRotation), step)
+			length := float64(cut.top - cut.bottom)
+			if length < across || (family.keepEqual && length == across) {
+				middles = append(middles, middle)
+			}
+		}
+
+		for _, line := range connectMiddles(middles, step, threshold) {
+			line.Rotate(-family.rotation)
+			result = append(result, line.RemoveCollinear())
+		}
+	}
+
+	return result, nil
+}
+
+// scanCut is the cut of a vertical scanline through a region, in the frame in which the region was scanned.
+type scanCut struct {
+	x, bottom, top data.Micrometer
+}
+
+// scanCuts rotates the region by the given rotation and cuts it by vertical scanlines which are step apart.
+func scanCuts(region data.LayerPart, rotation float64, step data.Micrometer) ([]scanCut, error) {
+	outline, holes := rotatedCopy(region, rotation)
+	min, max := outline.Bounds()
+
+	lines := verticalLines(min, max, min.X()+step/2, step)
+	clipped, err := clipLines(clipperPath(outline), clipperPaths(holes), lines, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var cuts []scanCut
+	for _, cut := range microPaths(clipped, false) {
+		if len(cut) < 2 {
+			continue
+		}
+
+		bottom, top := cut[0].Y(), cut[len(cut)-1].Y()
+		if bottom > top {
+			bottom, top = top, bottom
+		}
+		cuts = append(cuts, scanCut{x: cut[0].X(), bottom: bottom, top: top})
+	}
+
+	return cuts, nil
+}
+
+// crossingLength returns the length of the cut at the scanline nearest to the point which contains the point.
+// If there is no such cut, the length is infinite.
+func crossingLength(cuts []scanCut, point data.MicroPoint, step data.Micrometer) float64 {
+	length := math.Inf(1)
+	for _, cut := range cuts {
+		distance := cut.x - point.X()
+		if distance < 0 {
+			distance = -distance
+		}
+
+		if distance <= step/2 && cut.bottom <= point.Y() && point.Y() <= cut.top {
+			length = math.Min(length, float64(cut.top-cut.bottom))
+		}
+	}
+
+	return length
+}
+
+// connectMiddles connects the middle points of the cuts of one scan direction to lines.
+func connectMiddles(middles data.Path, step data.Micrometer, threshold data.Micrometer) data.Paths {
+	sort.SliceStable(middles, func(i, j int) bool {
+		if middles[i].X() != middles[j].X() {
+			return middles[i].X() < middles[j].X()
+		}
+		return middles[i].Y() < middles[j].Y()
+	})
+
+	// Connect each middle point to the nearest line which ended at the previous scanline.
+	// Every other line is finished.
+	var finished, open data.Paths
+	for _, middle := range middles {
+		best := -1
+		var bestDistance data.Micrometer
+		for i, line := range open {
+			last := line[len(line)-1]
+			if last.X() == middle.X() {
+				// already extended at this scanline
+				continue
+			}
+			if middle.X()-last.X() > step {
+				continue
+			}
+
+			d := middle.Y() - last.Y()
+			if d < 0 {
+				d = -d
+			}
+			if d <= threshold && (best == -1 || d < bestDistance) {
+				best = i
+				bestDistance = d
+			}
+		}
+
+		if best == -1 {
+			open = append(open, data.Path{middle})
+		} else {
+			open[best] = append(open[best], middle)
+		}
+
+		// finish all lines which can not be extended anymore
+		var stillOpen data.Paths
+		for _, line := range open {
+			if middle.X()-line[len(line)-1].X() > step {
+				finished = append(finished, line)
+			} else {
+				stillOpen = append(stillOpen, line)
+			}
+		}
+		open = stillOpen
+	}
+	finished = append(finished, open...)
+
+	var result data.Paths
+	for _, line := range finished {
+		if len(line) >= 2 {
+			result = append(result, line)
+		}
+	}
+
+	return result
+}
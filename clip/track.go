@@ -0,0 +1,105 @@
+// This file provides the tracking of layer parts across consecutive layers.
+
+package clip
+
+import (
+	"GoSlice/data"
+	"sort"
+
+	clipper "github.com/aligator/go.clipper"
+)
+
+// PartTracker assigns stable ids to the parts of consecutive layers,
+// so that "the same part" can be identified across the layers even if its shape changes.
+// This is the base for e.g. seam alignment or per object settings.
+//
+// A part gets the id of the part of the previous layer which it overlaps most.
+// If a part splits, the piece with the greatest overlap keeps the id and all other pieces get new ids.
+// If parts merge, the merged part gets the id of the part it overlaps most and the other ids end.
+// Parts without any overlap with the previous layer get new ids.
+type PartTracker struct {
+	nextID   int
+	previous []data.LayerPart
+}
+
+// NewPartTracker returns a new PartTracker which starts with the id 0.
+func NewPartTracker() *PartTracker {
+	return &PartTracker{}
+}
+
+// candidate is a possible match between a part of the current and a part of the previous layer.
+type candidate struct {
+	current, previous int
+	area              float64
+}
+
+// Track assigns the ids to the parts of the next layer, compared to the parts passed by the previous call.
+// The layers have to be passed in order.
+// It returns the parts with their id added to the attributes, which can be read by PartID.
+// If a coordinate exceeds MaxCoordinate, a *CoordinateError is returned.
+// If clipper fails, a *ClipError is returned.
+func (t *PartTracker) Track(parts []data.LayerPart) ([]data.LayerPart, error) {
+	if err := validateParts(parts); err != nil {
+		return nil, err
+	}
+
+	c := clipperClipper{}
+
+	var candidates []candidate
+	for i, part := range parts {
+		min, max := part.Outline().Bounds()
+		for j, previous := range t.previous {
+			previousMin, previousMax := previous.Outline().Bounds()
+			if min.X() > previousMax.X() || max.X() < previousMin.X() || min.Y() > previousMax.Y() || max.Y() < previousMin.Y() {
+				continue
+			}
+
+			overlap, ok := c.Intersection([]data.LayerPart{part}, []data.LayerPart{previous})
+			if !ok {
+				return nil, newClipError(clipper.CtIntersection, partsToClipperPaths([]data.LayerPart{part}), partsToClipperPaths([]data.LayerPart{previous}))
+			}
+
+			if area := partsArea(overlap); area > 0 {
+				candidates = append(candidates, candidate{current: i, previous: j, area: area})
+			}
+		}
+	}
+
+	// the greatest overlaps win
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].area > candidates[j].area
+	})
+
+	ids := make([]int, len(parts))
+	assigned := make([]bool, len(parts))
+	used := make([]bool, len(t.previous))
+	for _, match := range candidates {
+		if assigned[match.current] || used[match.previous] {
+			continue
+		}
+
+		id, _ := PartID(t.previous[match.previous])
+		ids[match.current] = id
+		assigned[match.current] = true
+		used[match.previous] = true
+	}
+
+	result := make([]data.LayerPart, len(parts))
+	for i, part := range parts {
+		if !assigned[i] {
+			ids[i] = t.nextID
+			t.nextID++
+		}
+		result[i] = withAttribute(part, idAttribute, ids[i])
+	}
+
+	t.previous = result
+	return result, nil
+}
+
+// PartID returns the id assigned to the part by a PartTracker.
+// If the part has no id, ok is false.
+func PartID(part data.LayerPart) (id int, ok bool) {
+	id, ok = part.Attributes()[idAttribute].(int)
+	return id, ok
+}
@@ -0,0 +1,116 @@
+// This file provides the validation of layers before they are sliced.
+
+package clip
+
+import (
+	"GoSlice/data"
+	"fmt"
+
+	clipper "github.com/aligator/go.clipper"
+)
+
+// Issue describes a problem of a polygon found by Validate.
+type Issue int
+
+const (
+	// TooFewPoints means that the polygon has less than 3 points.
+	TooFewPoints Issue = iota
+
+	// ZeroArea means that the polygon has no area, e.g. because all points are on one line.
+	ZeroArea
+
+	// CoordinateOutOfRange means that a point of the polygon exceeds MaxCoordinate.
+	CoordinateOutOfRange
+
+	// UnionFailed means that clipper failed to combine the polygon.
+	UnionFailed
+)
+
+// String returns a readable description of the issue.
+func (i Issue) String() string {
+	switch i {
+	case TooFewPoints:
+		return "less than 3 points"
+	case ZeroArea:
+		return "zero area"
+	case CoordinateOutOfRange:
+		return "coordinate out of range"
+	case UnionFailed:
+		return "union failed"
+	default:
+		return "unknown issue"
+	}
+}
+
+// Warning is a problem of a single polygon of a layer.
+type Warning struct {
+	// PolygonIndex is the index of the offending polygon in data.Layer.Polygons().
+	// It is -1 if the problem concerns the whole layer.
+	PolygonIndex int
+
+	Issue Issue
+}
+
+func (w Warning) String() string {
+	if w.PolygonIndex < 0 {
+		return fmt.Sprintf("layer: %v", w.Issue)
+	}
+	return fmt.Sprintf("polygon %v: %v", w.PolygonIndex, w.Issue)
+}
+
+// Validate checks the polygons of the layer for common problems of bad meshes
+// without running the whole pipeline of GenerateLayerParts, Inset and Fill.
+// Such polygons are usually dropped silently, which results in missing features or empty layers.
+// This allows to warn the user up front.
+//
+// Each polygon gets at most one warning, for the first issue found.
+// If all polygons are valid but they can not be combined, a UnionFailed warning for the whole layer is added.
+// If there are no problems, nil is returned.
+func Validate(l data.Layer) []Warning {
+	var warnings []Warning
+
+	var valid clipper.Paths
+	for i, polygon := range l.Polygons() {
+		issue, ok := validatePolygon(polygon)
+		if !ok {
+			warnings = append(warnings, Warning{PolygonIndex: i, Issue: issue})
+			continue
+		}
+		valid = append(valid, clipperPath(polygon))
+	}
+
+	if len(warnings) == 0 && len(valid) > 0 {
+		cl := clipper.NewClipper(clipper.IoNone)
+		cl.AddPaths(valid, clipper.PtSubject, true)
+		if _, ok := cl.Execute2(clipper.CtUnion, clipper.PftEvenOdd, clipper.PftEvenOdd); !ok {
+			warnings = append(warnings, Warning{PolygonIndex: -1, Issue: UnionFailed})
+		}
+	}
+
+	return warnings
+}
+
+// validatePolygon checks a single polygon and returns its first issue.
+// If ok is true, the polygon has no issue.
+func validatePolygon(polygon data.Path) (issue Issue, ok bool) {
+	if len(polygon) < 3 {
+		return TooFewPoints, false
+	}
+
+	if err := validateCoordinates(polygon); err != nil {
+		return CoordinateOutOfRange, false
+	}
+
+	path := clipperPath(polygon)
+	if clipper.Area(path) == 0 {
+		return ZeroArea, false
+	}
+
+	cl := clipper.NewClipper(clipper.IoNone)
+	cl.AddPath(path, clipper.PtSubject, true)
+	if _, ok := cl.Execute2(clipper.CtUnion, clipper.PftEvenOdd, clipper.PftEvenOdd); !ok {
+		return UnionFailed, false
+	}
+
+	return 0, true
+}
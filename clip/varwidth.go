@@ -0,0 +1,110 @@
+// This file provides the calculation of variable line widths for walls.
+
+package clip
+
+import (
+	"GoSlice/data"
+	"math"
+)
+
+// WallWidths calculates a target line width for each segment of a closed wall of the part,
+// so that the walls exactly fill regions which are not an integer number of line widths wide.
+// This can be used to modulate the flow instead of filling the remaining gaps.
+//
+// The segment i is the segment from wall[i] to wall[i+1] (and the last one back to wall[0]).
+// The local thickness of the part is taken from the nearest point of its medial axis.
+// Where the part is thick enough for all insetCount walls on both sides, the normal lineWidth is used.
+// Otherwise the thickness is divided between the walls of both sides which fit into it.
+//
+// The walls of Inset always use the constant lineWidth, WallWidths is only an additional annotation.
+// If a coordinate exceeds MaxCoordinate, a *CoordinateError is returned.
+// If clipper fails, a *ClipError is returned.
+func WallWidths(part data.LayerPart, wall data.Path, lineWidth data.Micrometer, insetCount int) ([]data.Micrometer, error) {
+	if err := validateParts([]data.LayerPart{part}); err != nil {
+		return nil, err
+	}
+	if err := validateCoordinates(wall); err != nil {
+		return nil, err
+	}
+
+	widths := make([]data.Micrometer, len(wall))
+	for i := range widths {
+		widths[i] = lineWidth
+	}
+
+	if len(wall) < 2 || lineWidth <= 0 || insetCount <= 0 {
+		return widths, nil
+	}
+
+	axis, err := MedialAxis(part, lineWidth/4)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(axis) == 0 {
+		return widths, nil
+	}
+
+	for i := range wall {
+		a, b := wall[i], wall[(i+1)%len(wall)]
+		middle := data.NewMicroPoint((a.X()+b.X())/2, (a.Y()+b.Y())/2)
+
+		thickness := 2 * distanceToBoundary(part, nearestOnLines(axis, middle))
+		if thickness >= float64(2*insetCount)*float64(lineWidth) {
+			continue
+		}
+
+		// the number of walls on each side which fit into the thickness
+		walls := math.Max(1, math.Min(float64(insetCount), math.Round(thickness/float64(2*lineWidth))))
+		widths[i] = data.Micrometer(math.Round(thickness / (2 * walls)))
+	}
+
+	return widths, nil
+}
+
+// distanceToBoundary returns the distance of the point to the nearest outline or hole of the part.
+func distanceToBoundary(part data.LayerPart, point data.MicroPoint) float64 {
+	nearest := distanceToLoop(point, part.Outline())
+	for _, hole := range part.Holes() {
+		nearest = math.Min(nearest, distanceToLoop(point, hole))
+	}
+
+	return nearest
+}
+
+// nearestOnLines returns the point on the open lines which is nearest to the given point.
+// The lines must not be empty.
+func nearestOnLines(lines data.Paths, point data.MicroPoint) data.MicroPoint {
+	var nearest data.MicroPoint
+	minDistance := math.Inf(1)
+	for _, line := range lines {
+		for i := range line {
+			a, b := line[i], line[i]
+			if i+1 < len(line) {
+				b = line[i+1]
+			}
+
+			candidate := nearestOnSegment(point, a, b)
+			if d := math.Hypot(float64(candidate.X()-point.X()), float64(candidate.Y()-point.Y())); d < minDistance {
+				minDistance = d
+				nearest = candidate
+			}
+		}
+	}
+
+	return nearest
+}
+
+// nearestOnSegment returns the point on the line segment from a to b which is nearest to the given point.
+func nearestOnSegment(point, a, b data.MicroPoint) data.MicroPoint {
+	abX, abY := float64(b.X()-a.X()), float64(b.Y()-a.Y())
+	apX, apY := float64(point.X()-a.X()), float64(point.Y()-a.Y())
+
+	length2 := abX*abX + abY*abY
+	t := 0.0
+	if length2 > 0 {
+		t = math.Max(0, math.Min(1, (apX*abX+apY*abY)/length2))
+	}
+
+	return data.NewMicroPoint(a.X()+data.Micrometer(math.Round(t*abX)), a.Y()+data.Micrometer(math.Round(t*abY)))
+}
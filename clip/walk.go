@@ -0,0 +1,60 @@
+// This file provides a helper to traverse the result of InsetLayer.
+
+package clip
+
+import "GoSlice/data"
+
+// InsetVisitor is called by WalkInsets for each inset part.
+// The outline is the wall around the inset part and the holes are the walls around its holes.
+// The role is the WallRole of the inset part. If the part has no role, it is derived from the insetNr.
+// Closed reports if the walls are closed loops, which need a final move back to their first point.
+// If it returns an error, the walk is stopped and the error is returned by WalkInsets.
+type InsetVisitor func(partNr int, insetNr int, role WallRole, outline data.Path, holes data.Paths, closed bool) error
+
+// WalkInsets walks through the result of InsetLayer ([part][insetNr][insetParts]data.LayerPart)
+// and calls the visitor for each inset part.
+//
+// The parts are visited in their order and for each part the insets from the outer wall to the inner walls.
+// If outerLast is true, the outer wall (insetNr 0) of each part is visited after all inner walls instead.
+// Empty outlines and empty holes are skipped, so the visitor only gets walls which can be printed.
+func WalkInsets(insets [][][]data.LayerPart, outerLast bool, visit InsetVisitor) error {
+	for partNr, part := range insets {
+		for i := range part {
+			insetNr := i
+			if outerLast {
+				if i >= len(part)-1 {
+					insetNr = 0
+				} else {
+					insetNr = i + 1
+				}
+			}
+
+			for _, insetPart := range part[insetNr] {
+				if len(insetPart.Outline()) == 0 {
+					continue
+				}
+
+				var holes data.Paths
+				for _, hole := range insetPart.Holes() {
+					if len(hole) > 0 {
+						holes = append(holes, hole)
+					}
+				}
+
+				role, ok := Role(insetPart)
+				if !ok {
+					role = InnerWall
+					if insetNr == 0 {
+						role = OuterWall
+					}
+				}
+
+				if err := visit(partNr, insetNr, role, insetPart.Outline(), holes, insetPart.IsClosed()); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
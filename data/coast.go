@@ -0,0 +1,84 @@
+// This file provides the splitting of closed loops for coasting and wiping.
+
+package data
+
+import "math"
+
+// Coast splits the closed loop into an extruding part and a trailing coasting part,
+// which is printed without extrusion to use the residual pressure of the nozzle.
+// The coasting part has the given length and ends at the first point of the loop.
+//
+// Both returned paths are open paths: the extruding part starts at the first point of the loop
+// and the coasting part continues where the extruding part ends.
+//
+// Coasting is skipped entirely if the loop is shorter than minLength, the coast distance is <= 0
+// or the coast distance is not shorter than the whole loop.
+// Then the whole loop (with the first point repeated at the end) is returned as extruding part and the coasting part is nil.
+func (p Path) Coast(coastDistance Micrometer, minLength Micrometer) (extrude Path, coast Path) {
+	closed := closedCopy(p)
+
+	length := p.length(true)
+	if len(p) < 2 || coastDistance <= 0 || length < float64(minLength) || float64(coastDistance) >= length {
+		return closed, nil
+	}
+
+	return closed.splitAt(length - float64(coastDistance))
+}
+
+// Wipe returns the non-extruding wipe move which follows a printed closed loop.
+// The loop is printed from its first point back to its first point,
+// so the wipe starts there and continues along the already printed loop for the given distance.
+// As it stays on the printed loop it never leaves the part.
+//
+// The wipe never goes further than one round of the loop, even if the distance is longer.
+// If the distance is <= 0, nil is returned.
+func (p Path) Wipe(wipeDistance Micrometer) Path {
+	if len(p) < 2 || wipeDistance <= 0 {
+		return nil
+	}
+
+	closed := closedCopy(p)
+	if float64(wipeDistance) >= p.length(true) {
+		return closed
+	}
+
+	wipe, _ := closed.splitAt(float64(wipeDistance))
+	return wipe
+}
+
+// closedCopy returns a copy of the loop with the first point repeated at the end.
+func closedCopy(p Path) Path {
+	closed := make(Path, 0, len(p)+1)
+	closed = append(closed, p...)
+	if len(p) > 0 {
+		closed = append(closed, p[0])
+	}
+
+	return closed
+}
+
+// splitAt splits the open path at the given length along the path.
+// The first returned path ends at the split point and the second one starts there.
+// If the length is longer than the path, the whole path and nil are returned.
+func (p Path) splitAt(length float64) (Path, Path) {
+	walked := 0.0
+	for i := 1; i < len(p); i++ {
+		segment := distance(p[i-1], p[i])
+		if walked+segment < length {
+			walked += segment
+			continue
+		}
+
+		ratio := (length - walked) / segment
+		split := NewMicroPoint(
+			p[i-1].X()+Micrometer(math.Round(float64(p[i].X()-p[i-1].X())*ratio)),
+			p[i-1].Y()+Micrometer(math.Round(float64(p[i].Y()-p[i-1].Y())*ratio)),
+		)
+
+		head := append(append(Path{}, p[:i]...), split)
+		tail := append(Path{split}, p[i:]...)
+		return head, tail
+	}
+
+	return p, nil
+}
@@ -0,0 +1,117 @@
+package data_test
+
+import (
+	"GoSlice/data"
+	"GoSlice/util/test"
+	"testing"
+)
+
+func TestPathCoast(t *testing.T) {
+	square := data.Path{
+		data.NewMicroPoint(0, 0),
+		data.NewMicroPoint(1000, 0),
+		data.NewMicroPoint(1000, 1000),
+		data.NewMicroPoint(0, 1000),
+	}
+	closedSquare := append(append(data.Path{}, square...), square[0])
+
+	var testCases = []struct {
+		coastDistance   data.Micrometer
+		minLength       data.Micrometer
+		expectedExtrude data.Path
+		expectedCoast   data.Path
+	}{
+		{
+			coastDistance: 300,
+			expectedExtrude: data.Path{
+				data.NewMicroPoint(0, 0),
+				data.NewMicroPoint(1000, 0),
+				data.NewMicroPoint(1000, 1000),
+				data.NewMicroPoint(0, 1000),
+				data.NewMicroPoint(0, 300),
+			},
+			expectedCoast: data.Path{
+				data.NewMicroPoint(0, 300),
+				data.NewMicroPoint(0, 0),
+			},
+		},
+		{
+			coastDistance: 1500,
+			expectedExtrude: data.Path{
+				data.NewMicroPoint(0, 0),
+				data.NewMicroPoint(1000, 0),
+				data.NewMicroPoint(1000, 1000),
+				data.NewMicroPoint(500, 1000),
+			},
+			expectedCoast: data.Path{
+				data.NewMicroPoint(500, 1000),
+				data.NewMicroPoint(0, 1000),
+				data.NewMicroPoint(0, 0),
+			},
+		},
+		// the coast distance exceeds the loop
+		{coastDistance: 4000, expectedExtrude: closedSquare},
+		// the loop is too short for coasting
+		{coastDistance: 300, minLength: 5000, expectedExtrude: closedSquare},
+		// no coasting
+		{coastDistance: 0, expectedExtrude: closedSquare},
+	}
+
+	for i, testCase := range testCases {
+		t.Log("testCase", i)
+		extrude, coast := square.Coast(testCase.coastDistance, testCase.minLength)
+		test.Equals(t, len(testCase.expectedExtrude), len(extrude))
+		test.Equals(t, testCase.expectedExtrude, extrude, pathComparer())
+		test.Equals(t, len(testCase.expectedCoast), len(coast))
+		test.Equals(t, testCase.expectedCoast, coast, pathComparer())
+	}
+}
+
+func TestPathWipe(t *testing.T) {
+	square := data.Path{
+		data.NewMicroPoint(0, 0),
+		data.NewMicroPoint(1000, 0),
+		data.NewMicroPoint(1000, 1000),
+		data.NewMicroPoint(0, 1000),
+	}
+
+	var testCases = []struct {
+		wipeDistance data.Micrometer
+		expected     data.Path
+	}{
+		{wipeDistance: 0, expected: nil},
+		{
+			wipeDistance: 400,
+			expected: data.Path{
+				data.NewMicroPoint(0, 0),
+				data.NewMicroPoint(400, 0),
+			},
+		},
+		{
+			wipeDistance: 1200,
+			expected: data.Path{
+				data.NewMicroPoint(0, 0),
+				data.NewMicroPoint(1000, 0),
+				data.NewMicroPoint(1000, 200),
+			},
+		},
+		// the wipe never extends past the loop
+		{
+			wipeDistance: 10000,
+			expected: data.Path{
+				data.NewMicroPoint(0, 0),
+				data.NewMicroPoint(1000, 0),
+				data.NewMicroPoint(1000, 1000),
+				data.NewMicroPoint(0, 1000),
+				data.NewMicroPoint(0, 0),
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		t.Log("testCase", i)
+		wipe := square.Wipe(testCase.wipeDistance)
+		test.Equals(t, len(testCase.expected), len(wipe))
+		test.Equals(t, testCase.expected, wipe, pathComparer())
+	}
+}
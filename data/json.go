@@ -0,0 +1,133 @@
+// This file provides JSON serialization for paths and layers.
+// It can be used to dump layers for debugging or to pin geometry in tests.
+
+package data
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// jsonLayerPart is the JSON representation of a LayerPart.
+type jsonLayerPart struct {
+	Outline  Path  `json:"outline"`
+	Holes    Paths `json:"holes"`
+	Extruder int   `json:"extruder,omitempty"`
+	Open     bool  `json:"open,omitempty"`
+}
+
+// jsonPartitionedLayer is the JSON representation of a PartitionedLayer.
+type jsonPartitionedLayer struct {
+	Parts []jsonLayerPart `json:"parts"`
+}
+
+// MarshalJSON encodes the path as a list of [x, y] pairs.
+// As the coordinates are integers the encoding is exact.
+func (p Path) MarshalJSON() ([]byte, error) {
+	points := make([][2]Micrometer, len(p))
+	for i, point := range p {
+		points[i] = [2]Micrometer{point.X(), point.Y()}
+	}
+
+	return json.Marshal(points)
+}
+
+// UnmarshalJSON decodes a path from a list of [x, y] pairs.
+func (p *Path) UnmarshalJSON(b []byte) error {
+	var points [][2]Micrometer
+	if err := json.Unmarshal(b, &points); err != nil {
+		return err
+	}
+
+	if points == nil {
+		*p = nil
+		return nil
+	}
+
+	result := make(Path, len(points))
+	for i, point := range points {
+		result[i] = NewMicroPoint(point[0], point[1])
+	}
+
+	*p = result
+	return nil
+}
+
+func toJSONLayerPart(part LayerPart) jsonLayerPart {
+	return jsonLayerPart{
+		Outline:  part.Outline(),
+		Holes:    part.Holes(),
+		Extruder: part.Extruder(),
+		Open:     !part.IsClosed(),
+	}
+}
+
+// fromJSONLayerPart creates a new LayerPart from its JSON representation.
+func fromJSONLayerPart(part jsonLayerPart) LayerPart {
+	result := NewBasicLayerPart(part.Outline, part.Holes)
+	if part.Open {
+		result = NewOpenLayerPart(part.Outline)
+	}
+	result.SetExtruder(part.Extruder)
+	return result
+}
+
+func (l *basicLayerPart) MarshalJSON() ([]byte, error) {
+	return MarshalLayerPart(l)
+}
+
+func (p partitionedLayer) MarshalJSON() ([]byte, error) {
+	return MarshalPartitionedLayer(p)
+}
+
+// MarshalLayerPart encodes any LayerPart implementation as JSON.
+// Only the outline, the holes, the extruder and if it is open are encoded, attributes are not serialized.
+func MarshalLayerPart(part LayerPart) ([]byte, error) {
+	if part == nil {
+		return nil, errors.New("the layer part is nil")
+	}
+
+	return json.Marshal(toJSONLayerPart(part))
+}
+
+// UnmarshalLayerPart decodes a LayerPart which was encoded by MarshalLayerPart.
+func UnmarshalLayerPart(b []byte) (LayerPart, error) {
+	var part jsonLayerPart
+	if err := json.Unmarshal(b, &part); err != nil {
+		return nil, err
+	}
+
+	return fromJSONLayerPart(part), nil
+}
+
+// MarshalPartitionedLayer encodes any PartitionedLayer implementation as JSON.
+// Only the layer parts are encoded, attributes are not serialized.
+func MarshalPartitionedLayer(layer PartitionedLayer) ([]byte, error) {
+	if layer == nil {
+		return nil, errors.New("the partitioned layer is nil")
+	}
+
+	result := jsonPartitionedLayer{
+		Parts: []jsonLayerPart{},
+	}
+	for _, part := range layer.LayerParts() {
+		result.Parts = append(result.Parts, toJSONLayerPart(part))
+	}
+
+	return json.Marshal(result)
+}
+
+// UnmarshalPartitionedLayer decodes a PartitionedLayer which was encoded by MarshalPartitionedLayer.
+func UnmarshalPartitionedLayer(b []byte) (PartitionedLayer, error) {
+	var layer jsonPartitionedLayer
+	if err := json.Unmarshal(b, &layer); err != nil {
+		return nil, err
+	}
+
+	parts := make([]LayerPart, len(layer.Parts))
+	for i, part := range layer.Parts {
+		parts[i] = fromJSONLayerPart(part)
+	}
+
+	return NewPartitionedLayer(parts), nil
+}
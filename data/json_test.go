@@ -0,0 +1,69 @@
+package data_test
+
+import (
+	"GoSlice/data"
+	"GoSlice/util/test"
+	"encoding/json"
+	"testing"
+)
+
+func TestPathJSON(t *testing.T) {
+	path := data.Path{
+		data.NewMicroPoint(0, 0),
+		data.NewMicroPoint(100, -20),
+		data.NewMicroPoint(123456789, 987654321),
+	}
+
+	b, err := json.Marshal(path)
+	test.Ok(t, err)
+	test.Equals(t, `[[0,0],[100,-20],[123456789,987654321]]`, string(b))
+
+	var decoded data.Path
+	test.Ok(t, json.Unmarshal(b, &decoded))
+	test.Equals(t, path, decoded, pathComparer())
+}
+
+func TestPartitionedLayerJSON(t *testing.T) {
+	outline := data.Path{
+		data.NewMicroPoint(0, 0),
+		data.NewMicroPoint(1000, 0),
+		data.NewMicroPoint(1000, 1000),
+		data.NewMicroPoint(0, 1000),
+	}
+	holes := data.Paths{
+		data.Path{
+			data.NewMicroPoint(250, 250),
+			data.NewMicroPoint(250, 750),
+			data.NewMicroPoint(750, 750),
+			data.NewMicroPoint(750, 250),
+		},
+	}
+
+	layer := data.NewPartitionedLayer([]data.LayerPart{
+		data.NewBasicLayerPart(outline, holes),
+		data.NewBasicLayerPart(outline, nil),
+		data.NewOpenLayerPart(outline),
+	})
+	layer.LayerParts()[1].SetExtruder(1)
+
+	b, err := json.Marshal(layer)
+	test.Ok(t, err)
+
+	viaFunc, err := data.MarshalPartitionedLayer(layer)
+	test.Ok(t, err)
+	test.Equals(t, string(b), string(viaFunc))
+
+	decoded, err := data.UnmarshalPartitionedLayer(b)
+	test.Ok(t, err)
+	test.Equals(t, len(layer.LayerParts()), len(decoded.LayerParts()))
+
+	for i, part := range layer.LayerParts() {
+		test.Equals(t, part, decoded.LayerParts()[i], layerPartComparer(true))
+	}
+
+	partJSON, err := data.MarshalLayerPart(layer.LayerParts()[0])
+	test.Ok(t, err)
+	decodedPart, err := data.UnmarshalLayerPart(partJSON)
+	test.Ok(t, err)
+	test.Equals(t, layer.LayerParts()[0], decodedPart, layerPartComparer(true))
+}
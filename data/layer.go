@@ -2,6 +2,8 @@
 
 package data
 
+import "math"
+
 // Path is a simple list of points.
 // It can be used to represent polygons (if they are closed) or just lines.
 type Path []MicroPoint
@@ -136,6 +138,74 @@ func (p Path) Simplify(smallestLineSegmentSquared, allowedErrorDistanceSquared M
 	return newPath
 }
 
+// RemoveCollinear removes all points which lie exactly on the line segment between their neighbors.
+// In contrast to Simplify it is lossless, as no remaining point is moved and the geometry does not change at all.
+// The first and the last point are always kept.
+// It returns a new path and does not modify this one.
+func (p Path) RemoveCollinear() Path {
+	if len(p) <= 2 {
+		return p
+	}
+
+	result := Path{p[0]}
+	for i := 1; i < len(p)-1; i++ {
+		previous := result[len(result)-1]
+		current := p[i]
+		next := p[i+1]
+
+		toCurrent := current.Sub(previous)
+		toNext := next.Sub(current)
+
+		// The point lies on the segment if the cross product is zero (collinear)
+		// and it does not reverse the direction (between the neighbors).
+		cross := toCurrent.X()*toNext.Y() - toCurrent.Y()*toNext.X()
+		if cross == 0 && DotProduct(toCurrent, toNext) >= 0 {
+			continue
+		}
+
+		result = append(result, current)
+	}
+
+	return append(result, p[len(p)-1])
+}
+
+// FilterNearPoints removes points which are nearer than minDistance to the last kept point,
+// but only if removing them does not change the shape by more than maxDeviation.
+// In contrast to only comparing each point with the last kept point this also preserves
+// gentle curves which are sampled with many very small steps.
+//
+// A point is kept if either its distance to the last kept point is at least minDistance
+// or if any of the points since the last kept point would deviate more than maxDeviation
+// from the line between the last kept point and the next point.
+// The first and the last point are always kept.
+// It returns a new path and does not modify this one.
+func (p Path) FilterNearPoints(minDistance, maxDeviation Micrometer) Path {
+	if len(p) <= 2 {
+		return p
+	}
+
+	minDistance2 := minDistance * minDistance
+	maxDeviation2 := maxDeviation * maxDeviation
+
+	result := Path{p[0]}
+	lastKept := 0
+	for i := 1; i < len(p)-1; i++ {
+		keep := p[i].Sub(p[lastKept]).Size2() >= minDistance2
+
+		// check if the line to the next point still represents all skipped points
+		for j := lastKept + 1; !keep && j <= i; j++ {
+			keep = PerpendicularDistance2(p[lastKept], p[i+1], p[j]) > maxDeviation2
+		}
+
+		if keep {
+			result = append(result, p[i])
+			lastKept = i
+		}
+	}
+
+	return append(result, p[len(p)-1])
+}
+
 // Bounds calculates the bounding box of the Path
 // The returned points are the min-X-Y-Point and the max-X-Y-Point.
 func (p Path) Bounds() (MicroPoint, MicroPoint) {
@@ -175,6 +245,37 @@ func (p Path) Rotate(degree float64) {
 	}
 }
 
+// Length returns the length of the path.
+// If closed is true, the segment from the last point back to the first point is included.
+func (p Path) Length(closed bool) Micrometer {
+	return Micrometer(math.Round(p.length(closed)))
+}
+
+// length returns the not rounded length of the path.
+// The distances are summed up as float to avoid overflows of the squared distances
+// and to avoid accumulating rounding errors.
+func (p Path) length(closed bool) float64 {
+	if len(p) < 2 {
+		return 0
+	}
+
+	length := 0.0
+	for i := 1; i < len(p); i++ {
+		length += distance(p[i-1], p[i])
+	}
+
+	if closed {
+		length += distance(p[len(p)-1], p[0])
+	}
+
+	return length
+}
+
+// distance returns the distance between two points without calculating the squared distance as integer.
+func distance(a, b MicroPoint) float64 {
+	return math.Hypot(float64(b.X()-a.X()), float64(b.Y()-a.Y()))
+}
+
 // Paths represents a group of Paths.
 type Paths []Path
 
@@ -219,6 +320,50 @@ func (p Paths) Bounds() (MicroPoint, MicroPoint) {
 	return NewMicroPoint(minX, minY), NewMicroPoint(maxX, maxY)
 }
 
+// TotalLength returns the sum of the lengths of all paths.
+// It can be used to estimate the filament usage and the print time of a layer.
+// If closed is true, all paths are handled as closed polygons.
+func (p Paths) TotalLength(closed bool) Micrometer {
+	length := 0.0
+	for _, path := range p {
+		length += path.length(closed)
+	}
+
+	return Micrometer(math.Round(length))
+}
+
+// Lengths returns the length of each path.
+// If closed is true, all paths are handled as closed polygons.
+func (p Paths) Lengths(closed bool) []Micrometer {
+	lengths := make([]Micrometer, len(p))
+	for i, path := range p {
+		lengths[i] = path.Length(closed)
+	}
+
+	return lengths
+}
+
+// TravelLength returns the sum of the non-extruding moves between the paths if they are printed in the given order.
+// These moves go from the end of each path to the start of the next path.
+// If closed is true, all paths are handled as closed polygons, so they end at their first point.
+func (p Paths) TravelLength(closed bool) Micrometer {
+	length := 0.0
+	for i := 1; i < len(p); i++ {
+		previous, next := p[i-1], p[i]
+		if len(previous) == 0 || len(next) == 0 {
+			continue
+		}
+
+		end := previous[len(previous)-1]
+		if closed {
+			end = previous[0]
+		}
+		length += distance(end, next[0])
+	}
+
+	return Micrometer(math.Round(length))
+}
+
 // Rotate rotates all points around (0|0) by the given degree.
 func (p Paths) Rotate(degree float64) {
 	for _, path := range p {
@@ -240,6 +385,17 @@ type LayerPart interface {
 	// If the implementation does not support attributes, it should return nil.
 	// If the implementation supports attributes but doesn't have any, it should return an empty map.
 	Attributes() map[string]interface{}
+
+	// Extruder returns the index of the extruder (or material) which should print this part.
+	// The default is 0, which is the only extruder of single extrusion printers.
+	Extruder() int
+
+	// SetExtruder changes the index of the extruder which should print this part.
+	SetExtruder(extruder int)
+
+	// IsClosed returns true if the outline and the holes are closed loops.
+	// For closed loops the move from the last point back to the first point has to be printed.
+	IsClosed() bool
 }
 
 // Layer represents one layer which can consist of several polygons.
@@ -269,33 +425,58 @@ type PartitionedLayer interface {
 
 // basicLayerPart is the simplest implementation of LayerPart.
 // It holds one outline and several hole-paths.
-// You can assume that all paths are closed polygons.
+// You can assume that all paths are closed polygons, unless it was created by NewOpenLayerPart.
 // (If the instance is created by GoSlice...)
 type basicLayerPart struct {
-	outline Path
-	holes   Paths
+	outline  Path
+	holes    Paths
+	extruder int
+	open     bool
 }
 
 // NewBasicLayerPart returns a new, simple LayerPart.
+// All paths are closed loops.
+// It is printed by the extruder 0 until SetExtruder is called.
 func NewBasicLayerPart(outline Path, holes Paths) LayerPart {
-	return basicLayerPart{
+	return &basicLayerPart{
 		outline: outline,
 		holes:   holes,
 	}
 }
 
-func (l basicLayerPart) Outline() Path {
+// NewOpenLayerPart returns a new, simple LayerPart which consists only of an open path.
+// It is printed by the extruder 0 until SetExtruder is called.
+func NewOpenLayerPart(path Path) LayerPart {
+	return &basicLayerPart{
+		outline: path,
+		open:    true,
+	}
+}
+
+func (l *basicLayerPart) Outline() Path {
 	return l.outline
 }
 
-func (l basicLayerPart) Holes() Paths {
+func (l *basicLayerPart) Holes() Paths {
 	return l.holes
 }
 
-func (l basicLayerPart) Attributes() map[string]interface{} {
+func (l *basicLayerPart) Attributes() map[string]interface{} {
 	return nil
 }
 
+func (l *basicLayerPart) Extruder() int {
+	return l.extruder
+}
+
+func (l *basicLayerPart) SetExtruder(extruder int) {
+	l.extruder = extruder
+}
+
+func (l *basicLayerPart) IsClosed() bool {
+	return !l.open
+}
+
 type partitionedLayer struct {
 	parts []LayerPart
 }
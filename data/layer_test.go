@@ -73,6 +73,9 @@ func layerPartComparer(forceOrder bool) cmp.Option {
 		if !cmp.Equal(p1.Holes(), p2.Holes(), pathsComparer(true)) {
 			return false
 		}
+		if p1.Extruder() != p2.Extruder() || p1.IsClosed() != p2.IsClosed() {
+			return false
+		}
 
 		return true
 	})
@@ -133,6 +136,116 @@ func TestPathSimplify(t *testing.T) {
 	// TODO
 }
 
+func TestPathRemoveCollinear(t *testing.T) {
+	var testCases = []struct {
+		toTest   data.Path
+		expected data.Path
+	}{
+		{toTest: data.Path{
+			data.NewMicroPoint(0, 0),
+			data.NewMicroPoint(100, 0),
+			data.NewMicroPoint(200, 0),
+			data.NewMicroPoint(300, 0),
+			data.NewMicroPoint(400, 0),
+		}, expected: data.Path{
+			data.NewMicroPoint(0, 0),
+			data.NewMicroPoint(400, 0),
+		}},
+		{toTest: data.Path{
+			data.NewMicroPoint(0, 0),
+			data.NewMicroPoint(100, 100),
+			data.NewMicroPoint(100, 100),
+			data.NewMicroPoint(200, 200),
+			data.NewMicroPoint(200, 300),
+		}, expected: data.Path{
+			data.NewMicroPoint(0, 0),
+			data.NewMicroPoint(200, 200),
+			data.NewMicroPoint(200, 300),
+		}},
+		{toTest: data.Path{
+			data.NewMicroPoint(0, 0),
+			data.NewMicroPoint(100, 0),
+			data.NewMicroPoint(50, 0),
+		}, expected: data.Path{
+			data.NewMicroPoint(0, 0),
+			data.NewMicroPoint(100, 0),
+			data.NewMicroPoint(50, 0),
+		}},
+		{toTest: data.Path{
+			data.NewMicroPoint(0, 0),
+			data.NewMicroPoint(100, 1),
+			data.NewMicroPoint(200, 0),
+		}, expected: data.Path{
+			data.NewMicroPoint(0, 0),
+			data.NewMicroPoint(100, 1),
+			data.NewMicroPoint(200, 0),
+		}},
+	}
+
+	for i, testCase := range testCases {
+		t.Log("testCase", i)
+		result := testCase.toTest.RemoveCollinear()
+		test.Equals(t, len(testCase.expected), len(result))
+		test.Equals(t, testCase.expected, result, pathComparer())
+	}
+}
+
+func TestPathFilterNearPoints(t *testing.T) {
+	var testCases = []struct {
+		toTest   data.Path
+		expected data.Path
+	}{
+		// redundant points on a line are removed
+		{
+			toTest: data.Path{
+				data.NewMicroPoint(0, 0),
+				data.NewMicroPoint(30, 1),
+				data.NewMicroPoint(60, 0),
+				data.NewMicroPoint(300, 0),
+			},
+			expected: data.Path{
+				data.NewMicroPoint(0, 0),
+				data.NewMicroPoint(300, 0),
+			},
+		},
+		// small steps are kept if they change the shape
+		{
+			toTest: data.Path{
+				data.NewMicroPoint(0, 0),
+				data.NewMicroPoint(50, 0),
+				data.NewMicroPoint(90, 20),
+				data.NewMicroPoint(200, 20),
+			},
+			expected: data.Path{
+				data.NewMicroPoint(0, 0),
+				data.NewMicroPoint(50, 0),
+				data.NewMicroPoint(90, 20),
+				data.NewMicroPoint(200, 20),
+			},
+		},
+		// points which are far enough away are always kept
+		{
+			toTest: data.Path{
+				data.NewMicroPoint(0, 0),
+				data.NewMicroPoint(200, 0),
+				data.NewMicroPoint(400, 0),
+			},
+			expected: data.Path{
+				data.NewMicroPoint(0, 0),
+				data.NewMicroPoint(200, 0),
+				data.NewMicroPoint(400, 0),
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		t.Log("testCase", i)
+		result := testCase.toTest.FilterNearPoints(100, 5)
+		test.Equals(t, len(testCase.expected), len(result))
+		test.Equals(t, testCase.expected, result, pathComparer())
+	}
+}
+
 func TestPathBounds(t *testing.T) {
 	var testCases = []struct {
 		toTest      data.Path
@@ -167,6 +280,45 @@ func TestPathBounds(t *testing.T) {
 	}
 }
 
+func TestPathLength(t *testing.T) {
+	square := data.Path{
+		data.NewMicroPoint(0, 0),
+		data.NewMicroPoint(100, 0),
+		data.NewMicroPoint(100, 100),
+		data.NewMicroPoint(0, 100),
+	}
+
+	test.Equals(t, data.Micrometer(300), square.Length(false))
+	test.Equals(t, data.Micrometer(400), square.Length(true))
+	test.Equals(t, data.Micrometer(0), data.Path{data.NewMicroPoint(5, 5)}.Length(true))
+
+	// the squared distance would overflow int64
+	huge := data.Path{
+		data.NewMicroPoint(0, 0),
+		data.NewMicroPoint(4000000000, 3000000000),
+	}
+	test.Equals(t, data.Micrometer(5000000000), huge.Length(false))
+}
+
+func TestPathsLength(t *testing.T) {
+	paths := data.Paths{
+		data.Path{
+			data.NewMicroPoint(0, 0),
+			data.NewMicroPoint(100, 0),
+		},
+		data.Path{
+			data.NewMicroPoint(100, 30),
+			data.NewMicroPoint(100, 70),
+			data.NewMicroPoint(130, 70),
+		},
+	}
+
+	test.Equals(t, data.Micrometer(170), paths.TotalLength(false))
+	test.Equals(t, []data.Micrometer{100, 70}, paths.Lengths(false))
+	test.Equals(t, data.Micrometer(30), paths.TravelLength(false))
+	test.Equals(t, data.Micrometer(104), paths.TravelLength(true))
+}
+
 func TestPathsBounds(t *testing.T) {
 	var testCases = []struct {
 		toTest      data.Paths
@@ -264,5 +416,22 @@ func TestNewBasicLayerPart(t *testing.T) {
 		test.Equals(t, testCase.outline, part.Outline(), pathComparer())
 		test.Equals(t, testCase.holes, part.Holes(), pathsComparer(true))
 		test.Equals(t, map[string]interface{}(nil), part.Attributes())
+		test.Equals(t, 0, part.Extruder())
+
+		part.SetExtruder(1)
+		test.Equals(t, 1, part.Extruder())
+		test.Equals(t, true, part.IsClosed())
 	}
 }
+
+func TestNewOpenLayerPart(t *testing.T) {
+	path := data.Path{
+		data.NewMicroPoint(0, 0),
+		data.NewMicroPoint(100, 0),
+	}
+
+	part := data.NewOpenLayerPart(path)
+	test.Equals(t, path, part.Outline(), pathComparer())
+	test.Equals(t, 0, len(part.Holes()))
+	test.Equals(t, false, part.IsClosed())
+}
@@ -96,12 +96,42 @@ type PrintOptions struct {
 	// LayerThickness is the thickness for all but the first layer.
 	LayerThickness Micrometer
 
+	// InitialLayerExtrusionWidth is the line width used only for the first layer.
+	// A wider first layer improves the bed adhesion.
+	// If it is 0, the Printer.ExtrusionWidth is used.
+	InitialLayerExtrusionWidth Micrometer
+
 	// InsetCount is the number of perimeters.
 	InsetCount int
 
+	// IncrementalInset calculates each perimeter from the previous perimeter instead of the outline.
+	// This keeps the distance between the perimeters constant, even on tight curves, but it is slower.
+	IncrementalInset bool
+
+	// ThinWallThreshold is the width below which regions are printed as a single center line instead of perimeters.
+	// It is usually two times the extrusion width.
+	// If it is 0, no thin walls are detected.
+	ThinWallThreshold Micrometer
+
+	// ThinRingCenterLine prints rings which are too thin for their perimeters as a single loop in the middle of the ring.
+	// Otherwise only their outer perimeters are printed, even if they overlap.
+	ThinRingCenterLine bool
+
+	// HorizontalExpansion grows (positive value) or shrinks (negative value) the outline of all but the first layer.
+	HorizontalExpansion Micrometer
+
+	// InitialLayerHorizontalExpansion grows (positive value) or shrinks (negative value) the outline of the first layer.
+	// A negative value can be used to compensate the elephant foot.
+	InitialLayerHorizontalExpansion Micrometer
+
 	// InfillOverlapPercent is the percentage of overlap into the perimeters.
 	InfillOverlapPercent int
 
+	// InfillOverlap is the absolute overlap into the perimeters.
+	// If it is not 0, it is used instead of InfillOverlapPercent.
+	// It may be negative to inset the infill but it must not exceed the extrusion width.
+	InfillOverlap Micrometer
+
 	// AdditionalInternalInfillOverlapPercent is the percentage used to make the internal
 	// infill (infill not blocked by the perimeters) even bigger so that it grows a bit into the model.
 	AdditionalInternalInfillOverlapPercent int
@@ -112,6 +142,18 @@ type PrintOptions struct {
 	// InfillRotationDegree is the rotation used for the infill.
 	InfillRotationDegree int
 
+	// InfillChaining orders the infill lines by always continuing with the nearest unused line.
+	// This reduces the travel moves but changes the print order and therefore the appearance.
+	InfillChaining bool
+
+	// InfillBorder prints a loop around the infill region before the infill lines.
+	// This gives the infill a clean edge.
+	InfillBorder bool
+
+	// TopBottomPattern is the pattern used for the solid top and bottom layers.
+	// It can be "linear" or "concentric".
+	TopBottomPattern string
+
 	// NumberBottomLayers is the amount of layers the bottom layers should grow into the model.
 	NumberBottomLayers int
 
@@ -175,10 +217,31 @@ type Options struct {
 	// a polygon used to check if a open polygon can be closed.
 	FinishPolygonSnapDistance Micrometer
 
+	// PointFilter is the filter used to remove redundant points of the sliced polygons.
+	// It can be "simplify", which compares the points with their neighbors,
+	// or "deviation", which also considers the deviation from the line since the last kept point.
+	PointFilter string
+
+	// PointFilterDistance is the distance below which a point may be removed by the "deviation" filter.
+	PointFilterDistance Micrometer
+
+	// PointFilterDeviation is the max deviation from the original shape allowed by the "deviation" filter.
+	PointFilterDeviation Micrometer
+
 	// InputFilePath specifies the path to the input stl file.
 	InputFilePath string
 }
 
+// LayerExtrusionWidth returns the line width which is used for the given layer.
+// It is Print.InitialLayerExtrusionWidth for the first layer (if set) and Printer.ExtrusionWidth for all others.
+func (o *Options) LayerExtrusionWidth(layerNr int) Micrometer {
+	if layerNr == 0 && o.Print.InitialLayerExtrusionWidth != 0 {
+		return o.Print.InitialLayerExtrusionWidth
+	}
+
+	return o.Printer.ExtrusionWidth
+}
+
 func DefaultOptions() Options {
 	return Options{
 		Print: PrintOptions{
@@ -194,6 +257,7 @@ func DefaultOptions() Options {
 			AdditionalInternalInfillOverlapPercent: 400,
 			InfillPercent:                          20,
 			InfillRotationDegree:                   45,
+			TopBottomPattern:                       "linear",
 			NumberBottomLayers:                     3,
 			NumberTopLayers:                        4,
 		},
@@ -219,6 +283,9 @@ func DefaultOptions() Options {
 		MeldDistance:              30,
 		JoinPolygonSnapDistance:   160,
 		FinishPolygonSnapDistance: 1000,
+		PointFilter:               "simplify",
+		PointFilterDistance:       100,
+		PointFilterDeviation:      5,
 	}
 }
 
@@ -232,6 +299,9 @@ func ParseFlags() Options {
 	flag.Var(&options.MeldDistance, "meld-distance", "The distance which two points have to be within to count them as one point.")
 	flag.Var(&options.JoinPolygonSnapDistance, "join-polygon-snap-distance", "The distance used to check if two open polygons can be snapped together to one bigger polygon. Checked by the start and endpoints of the polygons.")
 	flag.Var(&options.FinishPolygonSnapDistance, "finish-polygon-snap-distance", "The max distance between start end endpoint of a polygon used to check if a open polygon can be closed.")
+	flag.StringVar(&options.PointFilter, "point-filter", options.PointFilter, "The filter used to remove redundant points of the sliced polygons. It can be \"simplify\" or \"deviation\".")
+	flag.Var(&options.PointFilterDistance, "point-filter-distance", "The distance below which a point may be removed by the \"deviation\" point filter.")
+	flag.Var(&options.PointFilterDeviation, "point-filter-deviation", "The max deviation from the original shape allowed by the \"deviation\" point filter.")
 
 	// print options
 	flag.Var(&options.Print.IntialLayerSpeed, "initial-layer-speed", "The speed only for the first layer in mm per second.")
@@ -240,11 +310,21 @@ func ParseFlags() Options {
 	flag.Var(&options.Print.MoveSpeed, "move-speed", "The speed for all non printing moves.")
 	flag.Var(&options.Print.InitialLayerThickness, "initial-layer-thickness", "The layer thickness for the first layer.")
 	flag.Var(&options.Print.LayerThickness, "layer-thickness", "The thickness for all but the first layer.")
+	flag.Var(&options.Print.InitialLayerExtrusionWidth, "initial-layer-extrusion-width", "The line width used only for the first layer. If it is 0, the extrusion-width is used.")
 	flag.IntVar(&options.Print.InsetCount, "inset-count", options.Print.InsetCount, "The number of perimeters.")
+	flag.BoolVar(&options.Print.IncrementalInset, "incremental-inset", options.Print.IncrementalInset, "Calculate each perimeter from the previous perimeter instead of the outline. This keeps the distance between the perimeters constant, even on tight curves, but it is slower.")
+	flag.Var(&options.Print.ThinWallThreshold, "thin-wall-threshold", "The width below which regions are printed as a single center line instead of perimeters. If it is 0, no thin walls are detected.")
+	flag.BoolVar(&options.Print.ThinRingCenterLine, "thin-ring-center-line", options.Print.ThinRingCenterLine, "Print rings which are too thin for their perimeters as a single loop in the middle of the ring. Otherwise only their outer perimeters are printed, even if they overlap.")
+	flag.Var(&options.Print.HorizontalExpansion, "horizontal-expansion", "Grows (positive value) or shrinks (negative value) the outline of all but the first layer.")
+	flag.Var(&options.Print.InitialLayerHorizontalExpansion, "initial-layer-horizontal-expansion", "Grows (positive value) or shrinks (negative value) the outline of the first layer. A negative value can be used to compensate the elephant foot.")
 	flag.IntVar(&options.Print.InfillOverlapPercent, "infill-overlap-percent", options.Print.InfillOverlapPercent, "The percentage of overlap into the perimeters.")
+	flag.Var(&options.Print.InfillOverlap, "infill-overlap", "The absolute overlap into the perimeters. If set, it is used instead of infill-overlap-percent.")
 	flag.IntVar(&options.Print.AdditionalInternalInfillOverlapPercent, "additional-internal-infill-overlap-percent", options.Print.AdditionalInternalInfillOverlapPercent, "The percentage used to make the internal infill (infill not blocked by the perimeters) even bigger so that it grows a bit into the model.")
 	flag.IntVar(&options.Print.InfillPercent, "infill-percent", options.Print.InfillPercent, "The amount of infill which should be generated.")
 	flag.IntVar(&options.Print.InfillRotationDegree, "infill-rotation-degree", options.Print.InfillRotationDegree, "The rotation used for the infill.")
+	flag.BoolVar(&options.Print.InfillChaining, "infill-chaining", options.Print.InfillChaining, "Order the infill lines by always continuing with the nearest unused line. This reduces the travel moves but changes the print order.")
+	flag.BoolVar(&options.Print.InfillBorder, "infill-border", options.Print.InfillBorder, "Print a loop around the infill region before the infill lines. This gives the infill a clean edge.")
+	flag.StringVar(&options.Print.TopBottomPattern, "top-bottom-pattern", options.Print.TopBottomPattern, "The pattern used for the solid top and bottom layers. It can be \"linear\" or \"concentric\".")
 	flag.IntVar(&options.Print.NumberBottomLayers, "number-bottom-layers", options.Print.NumberBottomLayers, "The amount of layers the bottom layers should grow into the model.")
 	flag.IntVar(&options.Print.NumberTopLayers, "number-top-layers", options.Print.NumberTopLayers, "The amount of layers the bottom layers should grow into the model.")
 
@@ -275,5 +355,13 @@ func ParseFlags() Options {
 		panic("you have to pass a filename using the --file flag")
 	}
 
+	if options.Print.TopBottomPattern != "linear" && options.Print.TopBottomPattern != "concentric" {
+		panic("the --top-bottom-pattern has to be \"linear\" or \"concentric\"")
+	}
+
+	if options.PointFilter != "simplify" && options.PointFilter != "deviation" {
+		panic("the --point-filter has to be \"simplify\" or \"deviation\"")
+	}
+
 	return options
 }
@@ -0,0 +1,27 @@
+package data_test
+
+import (
+	"GoSlice/data"
+	"GoSlice/util/test"
+	"testing"
+)
+
+func TestLayerExtrusionWidth(t *testing.T) {
+	var testCases = []struct {
+		initialLayerExtrusionWidth data.Micrometer
+		layerNr                    int
+		expected                   data.Micrometer
+	}{
+		{initialLayerExtrusionWidth: 0, layerNr: 0, expected: 400},
+		{initialLayerExtrusionWidth: 0, layerNr: 1, expected: 400},
+		{initialLayerExtrusionWidth: 600, layerNr: 0, expected: 600},
+		{initialLayerExtrusionWidth: 600, layerNr: 1, expected: 400},
+	}
+
+	for i, testCase := range testCases {
+		t.Log("testCase", i)
+		options := data.DefaultOptions()
+		options.Print.InitialLayerExtrusionWidth = testCase.initialLayerExtrusionWidth
+		test.Equals(t, testCase.expected, options.LayerExtrusionWidth(testCase.layerNr))
+	}
+}
@@ -0,0 +1,157 @@
+// This file provides the placement of the seam of closed loops.
+
+package data
+
+import "math"
+
+// SeamPolicy defines where the start (and therefore also the end) point of a closed loop is placed.
+type SeamPolicy int
+
+const (
+	// SeamNearest starts the loop at the point which is nearest to a given position.
+	// This is position-driven and keeps the travel moves short.
+	SeamNearest SeamPolicy = iota
+
+	// SeamSharpestCorner starts the loop at the corner with the largest turn angle.
+	// This is geometry-driven, as the seam is least visible in a sharp (convex or concave) corner.
+	SeamSharpestCorner
+)
+
+// WithSeam returns a copy of the closed loop which starts at the point chosen by the given policy.
+// The position is only used by SeamNearest.
+// The loop must not repeat its first point at the end.
+func (p Path) WithSeam(policy SeamPolicy, position MicroPoint) Path {
+	switch policy {
+	case SeamSharpestCorner:
+		return p.StartAt(p.SharpestCorner())
+	default:
+		return p.StartAt(p.Nearest(position))
+	}
+}
+
+// StartAt returns a copy of the closed loop which starts at the point with the given index.
+// The order of the points is not changed.
+func (p Path) StartAt(index int) Path {
+	if len(p) == 0 {
+		return Path{}
+	}
+
+	result := make(Path, 0, len(p))
+	result = append(result, p[index:]...)
+	return append(result, p[:index]...)
+}
+
+// SharpestCorner returns the index of the point of the closed loop with the largest turn angle.
+// Convex and concave corners are treated the same.
+// If several corners have the same angle, the first one is used.
+func (p Path) SharpestCorner() int {
+	sharpest := 0
+	maxAngle := -1.0
+
+	for i, current := range p {
+		previous := p[(i+len(p)-1)%len(p)]
+		next := p[(i+1)%len(p)]
+
+		angle := turnAngle(current.Sub(previous), next.Sub(current))
+		if angle > maxAngle {
+			maxAngle = angle
+			sharpest = i
+		}
+	}
+
+	return sharpest
+}
+
+// Nearest returns the index of the point which is nearest to the given position.
+// If several points have the same distance, the first one is used.
+func (p Path) Nearest(position MicroPoint) int {
+	nearest := 0
+	minDistance := math.Inf(1)
+
+	for i, point := range p {
+		d := distance(point, position)
+		if d < minDistance {
+			minDistance = d
+			nearest = i
+		}
+	}
+
+	return nearest
+}
+
+// turnAngle returns the absolute angle in radians by which the direction changes
+// from the first to the second vector.
+// It is 0 for a straight continuation and Pi for a complete reversal.
+// If one of the vectors has no length, the angle is 0.
+func turnAngle(a, b MicroPoint) float64 {
+	if (a.X() == 0 && a.Y() == 0) || (b.X() == 0 && b.Y() == 0) {
+		return 0
+	}
+
+	cross := float64(a.X())*float64(b.Y()) - float64(a.Y())*float64(b.X())
+	dot := float64(a.X())*float64(b.X()) + float64(a.Y())*float64(b.Y())
+
+	return math.Abs(math.Atan2(cross, dot))
+}
+
+// FlowPath is a path with a flow multiplier for each point.
+// The flow changes linearly between two consecutive points,
+// so the extrusion of a move is scaled by the multipliers of its start and end point.
+type FlowPath struct {
+	Points Path
+	Flow   []float64
+}
+
+// Scarf returns the closed loop as scarf seam, which hides the bump of a hard seam.
+// The loop has to start at its seam (see WithSeam) and must not repeat its first point at the end.
+//
+// The flow ramps up from 0 to 1 over the first scarfLength of the loop.
+// After the loop is finished the path continues over the start ramp once more
+// while the flow ramps down from 1 to 0.
+// As both ramps overlap exactly, the sum of both always results in the full flow and the loop stays fully bonded.
+//
+// The scarf length is limited to the length of the loop.
+// If it is <= 0, the closed loop with full flow is returned.
+func (p Path) Scarf(scarfLength Micrometer) FlowPath {
+	loop := closedCopy(p)
+
+	length := p.length(true)
+	if len(p) < 2 || scarfLength <= 0 || length == 0 {
+		flow := make([]float64, len(loop))
+		for i := range flow {
+			flow[i] = 1
+		}
+		return FlowPath{Points: loop, Flow: flow}
+	}
+
+	ramp := math.Min(float64(scarfLength), length)
+	start, rest := loop.splitAt(ramp)
+
+	result := FlowPath{}
+	walked := 0.0
+	for i, point := range start {
+		if i > 0 {
+			walked += distance(start[i-1], point)
+		}
+		result.Points = append(result.Points, point)
+		result.Flow = append(result.Flow, walked/ramp)
+	}
+
+	// the rest of the loop (without the split point which is already added) uses the full flow
+	if len(rest) > 1 {
+		for _, point := range rest[1:] {
+			result.Points = append(result.Points, point)
+			result.Flow = append(result.Flow, 1)
+		}
+	}
+
+	// overlap the start ramp while reducing the flow
+	walked = 0
+	for i := 1; i < len(start); i++ {
+		walked += distance(start[i-1], start[i])
+		result.Points = append(result.Points, start[i])
+		result.Flow = append(result.Flow, 1-walked/ramp)
+	}
+
+	return result
+}
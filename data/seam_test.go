@@ -0,0 +1,112 @@
+package data_test
+
+import (
+	"GoSlice/data"
+	"GoSlice/util/test"
+	"testing"
+
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestPathWithSeam(t *testing.T) {
+	// a square with a sharp spike at (1000|1500)
+	loop := data.Path{
+		data.NewMicroPoint(0, 0),
+		data.NewMicroPoint(1000, 0),
+		data.NewMicroPoint(1000, 1000),
+		data.NewMicroPoint(1100, 1500),
+		data.NewMicroPoint(900, 1000),
+		data.NewMicroPoint(0, 1000),
+	}
+
+	var testCases = []struct {
+		policy   data.SeamPolicy
+		position data.MicroPoint
+		expected data.Path
+	}{
+		{
+			policy:   data.SeamSharpestCorner,
+			position: data.NewMicroPoint(0, 0),
+			expected: data.Path{
+				data.NewMicroPoint(1100, 1500),
+				data.NewMicroPoint(900, 1000),
+				data.NewMicroPoint(0, 1000),
+				data.NewMicroPoint(0, 0),
+				data.NewMicroPoint(1000, 0),
+				data.NewMicroPoint(1000, 1000),
+			},
+		},
+		{
+			policy:   data.SeamNearest,
+			position: data.NewMicroPoint(1200, -100),
+			expected: data.Path{
+				data.NewMicroPoint(1000, 0),
+				data.NewMicroPoint(1000, 1000),
+				data.NewMicroPoint(1100, 1500),
+				data.NewMicroPoint(900, 1000),
+				data.NewMicroPoint(0, 1000),
+				data.NewMicroPoint(0, 0),
+			},
+		},
+	}
+
+	for i, testCase := range testCases {
+		t.Log("testCase", i)
+		result := loop.WithSeam(testCase.policy, testCase.position)
+		test.Equals(t, len(testCase.expected), len(result))
+		test.Equals(t, testCase.expected, result, pathComparer())
+	}
+
+	// the original loop is not changed
+	test.Equals(t, data.Micrometer(0), loop[0].X())
+	test.Equals(t, 0, data.Path{}.SharpestCorner())
+}
+
+func TestPathScarf(t *testing.T) {
+	square := data.Path{
+		data.NewMicroPoint(0, 0),
+		data.NewMicroPoint(1000, 0),
+		data.NewMicroPoint(1000, 1000),
+		data.NewMicroPoint(0, 1000),
+	}
+
+	var testCases = []struct {
+		scarfLength    data.Micrometer
+		expectedPoints data.Path
+		expectedFlow   []float64
+	}{
+		{
+			scarfLength: 0,
+			expectedPoints: data.Path{
+				data.NewMicroPoint(0, 0),
+				data.NewMicroPoint(1000, 0),
+				data.NewMicroPoint(1000, 1000),
+				data.NewMicroPoint(0, 1000),
+				data.NewMicroPoint(0, 0),
+			},
+			expectedFlow: []float64{1, 1, 1, 1, 1},
+		},
+		{
+			scarfLength: 1500,
+			expectedPoints: data.Path{
+				data.NewMicroPoint(0, 0),
+				data.NewMicroPoint(1000, 0),
+				data.NewMicroPoint(1000, 500),
+				data.NewMicroPoint(1000, 1000),
+				data.NewMicroPoint(0, 1000),
+				data.NewMicroPoint(0, 0),
+				data.NewMicroPoint(1000, 0),
+				data.NewMicroPoint(1000, 500),
+			},
+			expectedFlow: []float64{0, 2.0 / 3.0, 1, 1, 1, 1, 1.0 / 3.0, 0},
+		},
+	}
+
+	for i, testCase := range testCases {
+		t.Log("testCase", i)
+		scarf := square.Scarf(testCase.scarfLength)
+		test.Equals(t, len(testCase.expectedPoints), len(scarf.Points))
+		test.Equals(t, testCase.expectedPoints, scarf.Points, pathComparer())
+		test.Equals(t, testCase.expectedFlow, scarf.Flow, cmpopts.EquateApprox(0, 1e-9))
+	}
+}
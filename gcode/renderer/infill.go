@@ -7,6 +7,7 @@ import (
 	"GoSlice/data"
 	"GoSlice/gcode"
 	"GoSlice/modifier"
+	"context"
 )
 
 // Infill is a renderer which can fill parts which are defined by a layer part attribute of a specific name.
@@ -16,21 +17,37 @@ type Infill struct {
 	// Min and max define the dimension of the model (in X and Y direction)
 	PatternSetup func(min data.MicroPoint, max data.MicroPoint) clip.Pattern
 
+	// InitialLayerPatternSetup is optional and sets the pattern which is used only for the first layer.
+	// This can be used to fill the first layer with a different line width.
+	// If it is nil, the pattern of PatternSetup is used for all layers.
+	InitialLayerPatternSetup func(min data.MicroPoint, max data.MicroPoint) clip.Pattern
+
 	// AttrName is the name of the attribute containing the []data.LayerPart's to fill.
 	AttrName string
 
 	// Comments is a list of comments to be added before each infill.
 	Comments []string
 
-	pattern clip.Pattern
+	pattern             clip.Pattern
+	initialLayerPattern clip.Pattern
 }
 
 func (i *Infill) Init(model data.OptimizedModel) {
 	i.pattern = i.PatternSetup(model.Min().PointXY(), model.Max().PointXY())
+
+	i.initialLayerPattern = i.pattern
+	if i.InitialLayerPatternSetup != nil {
+		i.initialLayerPattern = i.InitialLayerPatternSetup(model.Min().PointXY(), model.Max().PointXY())
+	}
 }
 
 func (i *Infill) Render(b *gcode.Builder, layerNr int, layers []data.PartitionedLayer, z data.Micrometer, options *data.Options) error {
-	if i.pattern == nil {
+	pattern := i.pattern
+	if layerNr == 0 {
+		pattern = i.initialLayerPattern
+	}
+
+	if pattern == nil {
 		return nil
 	}
 
@@ -47,7 +64,28 @@ func (i *Infill) Render(b *gcode.Builder, layerNr int, layers []data.Partitioned
 			b.AddComment(c)
 		}
 
-		for _, path := range i.pattern.Fill(layerNr, part) {
+		var paths data.Paths
+		var travel []bool
+		if chaining, ok := pattern.(clip.ChainingPattern); ok {
+			chain, err := chaining.FillChained(context.Background(), layerNr, part)
+			if err != nil {
+				return err
+			}
+			paths, travel = chain.Paths, chain.Travel
+		} else {
+			paths, err = pattern.Fill(context.Background(), layerNr, part)
+			if err != nil {
+				return err
+			}
+		}
+
+		for i, path := range paths {
+			// A chained connector is the travel move to the start of the next line,
+			// which AddPolygon already adds including the retraction check.
+			if travel != nil && travel[i] {
+				continue
+			}
+
 			err := b.AddPolygon(layers[layerNr], path, z, true)
 			if err != nil {
 				return err
@@ -34,7 +34,7 @@ func (PreLayer) Render(b *gcode.Builder, layerNr int, layers []data.PartitionedL
 		b.AddCommand("G1 Z5 F5000 ; lift nozzle")
 		b.AddCommand("G92 E0 ; reset extrusion distance")
 
-		b.SetExtrusion(options.Print.InitialLayerThickness, options.Printer.ExtrusionWidth, options.Filament.FilamentDiameter)
+		b.SetExtrusion(options.Print.InitialLayerThickness, options.LayerExtrusionWidth(0), options.Filament.FilamentDiameter)
 
 		// set speeds
 		b.SetExtrudeSpeed(options.Print.LayerSpeed)
@@ -51,6 +51,11 @@ func (PreLayer) Render(b *gcode.Builder, layerNr int, layers []data.PartitionedL
 		b.SetExtrudeSpeed(options.Print.LayerSpeed)
 	}
 
+	// switch back to the normal line width after a wider first layer
+	if layerNr == 1 && options.LayerExtrusionWidth(0) != options.Printer.ExtrusionWidth {
+		b.SetExtrusion(options.Print.InitialLayerThickness, options.Printer.ExtrusionWidth, options.Filament.FilamentDiameter)
+	}
+
 	if layerNr == 2 {
 		b.AddCommand("M106 ; enable fan")
 	}
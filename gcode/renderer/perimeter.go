@@ -3,12 +3,13 @@
 package renderer
 
 import (
+	"GoSlice/clip"
 	"GoSlice/data"
 	"GoSlice/gcode"
 	"GoSlice/modifier"
 )
 
-// Perimeter is a renderer which generates the gcode for the attribute "perimeters".
+// Perimeter is a renderer which generates the gcode for the attributes "perimeters" and "thinWalls".
 type Perimeter struct{}
 
 func (p Perimeter) Init(model data.OptimizedModel) {}
@@ -22,38 +23,40 @@ func (p Perimeter) Render(b *gcode.Builder, layerNr int, layers []data.Partition
 		return nil
 	}
 
-	for _, part := range perimeters {
-		for insetNr := range part {
-			// print the outer perimeter as last perimeter
-			if insetNr >= len(part)-1 {
-				insetNr = 0
-			} else {
-				insetNr++
-			}
+	thinWalls, err := modifier.ThinWalls(layers[layerNr])
+	if err != nil {
+		return err
+	}
 
-			for _, insetParts := range part[insetNr] {
-				if insetNr == 0 {
-					b.AddComment("TYPE:WALL-OUTER")
-					b.SetExtrudeSpeed(options.Print.OuterPerimeterSpeed)
-				} else {
-					b.AddComment("TYPE:WALL-INNER")
-					b.SetExtrudeSpeed(options.Print.LayerSpeed)
-				}
-
-				for _, hole := range insetParts.Holes() {
-					err := b.AddPolygon(layers[layerNr], hole, z, false)
-					if err != nil {
-						return err
-					}
-				}
-
-				err := b.AddPolygon(layers[layerNr], insetParts.Outline(), z, false)
-				if err != nil {
-					return err
-				}
+	// thin walls are printed as single lines which are part of the outer surface
+	if len(thinWalls) > 0 {
+		b.AddComment("TYPE:WALL-OUTER")
+		b.SetExtrudeSpeed(options.Print.OuterPerimeterSpeed)
+		for _, line := range thinWalls {
+			err := b.AddPolygon(layers[layerNr], line, z, true)
+			if err != nil {
+				return err
 			}
 		}
 	}
 
-	return nil
+	// print the outer perimeter as last perimeter
+	return clip.WalkInsets(perimeters, true, func(partNr int, insetNr int, role clip.WallRole, outline data.Path, holes data.Paths, closed bool) error {
+		if role == clip.OuterWall {
+			b.AddComment("TYPE:WALL-OUTER")
+			b.SetExtrudeSpeed(options.Print.OuterPerimeterSpeed)
+		} else {
+			b.AddComment("TYPE:WALL-INNER")
+			b.SetExtrudeSpeed(options.Print.LayerSpeed)
+		}
+
+		for _, hole := range holes {
+			err := b.AddPolygon(layers[layerNr], hole, z, !closed)
+			if err != nil {
+				return err
+			}
+		}
+
+		return b.AddPolygon(layers[layerNr], outline, z, !closed)
+	})
 }
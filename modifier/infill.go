@@ -69,6 +69,13 @@ func (m infillModifier) Modify(layerNr int, layers []data.PartitionedLayer) erro
 
 	c := clip.NewClipper()
 
+	extrusionWidth := m.options.LayerExtrusionWidth(layerNr)
+	overlap, err := infillOverlap(m.options, extrusionWidth)
+	if err != nil {
+		return err
+	}
+	internalOverlap := overlap + clip.OverlapFromPercent(extrusionWidth, m.options.Print.AdditionalInternalInfillOverlapPercent)
+
 	// Calculate the bottom/top parts for each inner perimeter part.
 	// It also takes into account the configured number of top/bottom layers.
 	for partNr, part := range perimeters {
@@ -139,7 +146,7 @@ func (m infillModifier) Modify(layerNr int, layers []data.PartitionedLayer) erro
 			// 2. Exset the area which needs infill to generate the internal overlap of top and bottom layer.
 			var internalOverlappingBottomParts, internalOverlappingTopParts []data.LayerPart
 			for _, bottomPart := range bottomInfillParts {
-				overlappingParts, err := calculateOverlapPerimeter(bottomPart, m.options.Print.InfillOverlapPercent+m.options.Print.AdditionalInternalInfillOverlapPercent, m.options.Printer.ExtrusionWidth)
+				overlappingParts, err := calculateOverlapPerimeter(bottomPart, internalOverlap, extrusionWidth)
 				if err != nil {
 					return err
 				}
@@ -148,7 +155,7 @@ func (m infillModifier) Modify(layerNr int, layers []data.PartitionedLayer) erro
 			}
 
 			for _, topPart := range topInfillParts {
-				overlappingParts, err := calculateOverlapPerimeter(topPart, m.options.Print.InfillOverlapPercent+m.options.Print.AdditionalInternalInfillOverlapPercent, m.options.Printer.ExtrusionWidth)
+				overlappingParts, err := calculateOverlapPerimeter(topPart, internalOverlap, extrusionWidth)
 				if err != nil {
 					return err
 				}
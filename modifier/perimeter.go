@@ -4,6 +4,7 @@ import (
 	"GoSlice/clip"
 	"GoSlice/data"
 	"GoSlice/handler"
+	"context"
 	"errors"
 )
 
@@ -54,18 +55,50 @@ func Perimeters(layer data.PartitionedLayer) ([][][]data.LayerPart, error) {
 	return nil, nil
 }
 
+// ThinWalls extracts the attribute "thinWalls" from the layer.
+// If it has the wrong type, a error is returned.
+// If it doesn't exist, (nil, nil) is returned.
+// If it exists, the center lines of all thin walls of the layer are returned.
+func ThinWalls(layer data.PartitionedLayer) (data.Paths, error) {
+	if attr, ok := layer.Attributes()["thinWalls"]; ok {
+		thinWalls, ok := attr.(data.Paths)
+		if !ok {
+			return nil, errors.New("the attribute thinWalls has the wrong datatype")
+		}
+
+		return thinWalls, nil
+	}
+
+	return nil, nil
+}
+
 func (m perimeterModifier) Init(model data.OptimizedModel) {}
 
 func (m perimeterModifier) Modify(layerNr int, layers []data.PartitionedLayer) error {
 	// Generate the perimeters.
 	c := clip.NewClipper()
-	insetParts := c.InsetLayer(layers[layerNr].LayerParts(), m.options.Printer.ExtrusionWidth, m.options.Print.InsetCount)
+	expansion := m.options.Print.HorizontalExpansion
+	if layerNr == 0 {
+		expansion = m.options.Print.InitialLayerHorizontalExpansion
+	}
+
+	extrusionWidth := m.options.LayerExtrusionWidth(layerNr)
+	insetParts, thinWalls, err := m.insetLayer(c, layers[layerNr].LayerParts(), extrusionWidth, expansion)
+	if err != nil {
+		return err
+	}
 
 	// Also generate the overlapping perimeter, which helps with calculating the infill.
-	// This is derived from the most inner perimeters and offset by the options.Print.InfillOverlapPercent option.
+	// This is derived from the most inner perimeters and offset by the options.Print.InfillOverlap
+	// or the options.Print.InfillOverlapPercent option.
 
 	var overlapPerimeter [][]data.LayerPart
 
+	overlap, err := infillOverlap(m.options, extrusionWidth)
+	if err != nil {
+		return err
+	}
+
 	for partNr, part := range insetParts {
 		if len(overlapPerimeter) >= partNr {
 			overlapPerimeter = append(overlapPerimeter, nil)
@@ -74,7 +107,7 @@ func (m perimeterModifier) Modify(layerNr int, layers []data.PartitionedLayer) e
 		// Use only the most inner perimeter.
 		for _, insetPart := range part[len(part)-1] {
 
-			maxOverlapBorder, err := calculateOverlapPerimeter(insetPart, m.options.Print.InfillOverlapPercent, m.options.Printer.ExtrusionWidth)
+			maxOverlapBorder, err := calculateOverlapPerimeter(insetPart, overlap, extrusionWidth)
 			if err != nil {
 				return err
 			}
@@ -85,14 +118,75 @@ func (m perimeterModifier) Modify(layerNr int, layers []data.PartitionedLayer) e
 	newLayer := newExtendedLayer(layers[layerNr])
 	newLayer.attributes["perimeters"] = insetParts
 	newLayer.attributes["overlapPerimeters"] = overlapPerimeter
+	if thinWalls != nil {
+		newLayer.attributes["thinWalls"] = thinWalls
+	}
 	layers[layerNr] = newLayer
 
 	return nil
 }
 
+// insetLayer calculates the perimeters of all parts.
+// If options.Print.ThinWallThreshold is set, the thin regions of the parts are not inset
+// but returned as center lines.
+// If options.Print.ThinRingCenterLine is set, rings which are too thin for their walls are printed as a single loop.
+func (m perimeterModifier) insetLayer(c clip.Clipper, parts []data.LayerPart, extrusionWidth data.Micrometer, expansion data.Micrometer) ([][][]data.LayerPart, data.Paths, error) {
+	opts := []clip.Option{clip.WithExpansion(expansion)}
+	if m.options.Print.IncrementalInset {
+		opts = append(opts, clip.WithIncrementalInset())
+	}
+	if m.options.Print.ThinRingCenterLine {
+		opts = append(opts, clip.WithThinRingCenterLine())
+	}
+
+	if m.options.Print.ThinWallThreshold <= 0 {
+		insetParts, err := c.InsetLayer(context.Background(), parts, extrusionWidth, m.options.Print.InsetCount, opts...)
+		return insetParts, nil, err
+	}
+
+	var insetParts [][][]data.LayerPart
+	var thinWalls data.Paths
+	for _, part := range parts {
+		thick, centerLines, err := clip.ThinWalls(part, extrusionWidth, m.options.Print.ThinWallThreshold)
+		if err != nil {
+			return nil, nil, err
+		}
+		thinWalls = append(thinWalls, centerLines...)
+
+		// combine the insets of all thick regions, so that there is still one entry per part
+		insets := make([][]data.LayerPart, m.options.Print.InsetCount)
+		err = c.InsetLayerFunc(context.Background(), thick, extrusionWidth, m.options.Print.InsetCount, func(_ int, thickInsets [][]data.LayerPart) error {
+			for insetNr, inset := range thickInsets {
+				insets[insetNr] = append(insets[insetNr], inset...)
+			}
+			return nil
+		}, opts...)
+		if err != nil {
+			return nil, nil, err
+		}
+		insetParts = append(insetParts, insets)
+	}
+
+	return insetParts, thinWalls, nil
+}
+
+// infillOverlap returns the absolute overlap of the infill into the perimeters for the given extrusion width.
+// If options.Print.InfillOverlap is set it is used, otherwise it is calculated from options.Print.InfillOverlapPercent.
+func infillOverlap(options *data.Options, extrusionWidth data.Micrometer) (data.Micrometer, error) {
+	if options.Print.InfillOverlap == 0 {
+		return clip.OverlapFromPercent(extrusionWidth, options.Print.InfillOverlapPercent), nil
+	}
+
+	if err := clip.ValidateOverlap(options.Print.InfillOverlap, extrusionWidth); err != nil {
+		return 0, err
+	}
+	return options.Print.InfillOverlap, nil
+}
+
 // calculateOverlapPerimeter helper function for calculating the overlap-perimeter out of a layer part.
-func calculateOverlapPerimeter(part data.LayerPart, overlapPercent int, extrusionWidth data.Micrometer) ([]data.LayerPart, error) {
-	perimeterOverlap := data.Micrometer(float32(extrusionWidth) * (100.0 - float32(overlapPercent)) / 100.0)
+// The overlap is the absolute distance the result should grow into the perimeter.
+func calculateOverlapPerimeter(part data.LayerPart, overlap data.Micrometer, extrusionWidth data.Micrometer) ([]data.LayerPart, error) {
+	perimeterOverlap := extrusionWidth - overlap
 
 	if perimeterOverlap != 0 {
 		c := clip.NewClipper()
@@ -34,8 +34,39 @@ func NewGoSlice(options data.Options) *GoSlice {
 	}
 
 	// create handlers
-	topBottomPatternFactory := func(min data.MicroPoint, max data.MicroPoint) clip.Pattern {
-		return clip.NewLinearPattern(options.Printer.ExtrusionWidth, options.Printer.ExtrusionWidth, min, max, options.Print.InfillRotationDegree)
+	topBottomPatternFactory := func(extrusionWidth data.Micrometer) func(min data.MicroPoint, max data.MicroPoint) clip.Pattern {
+		return func(min data.MicroPoint, max data.MicroPoint) clip.Pattern {
+			if options.Print.TopBottomPattern == "concentric" {
+				return clip.NewConcentricPattern(extrusionWidth, extrusionWidth)
+			}
+
+			return clip.NewLinearPattern(extrusionWidth, extrusionWidth, min, max, options.Print.InfillRotationDegree)
+		}
+	}
+
+	infillPatternFactory := func(extrusionWidth data.Micrometer) func(min data.MicroPoint, max data.MicroPoint) clip.Pattern {
+		return func(min data.MicroPoint, max data.MicroPoint) clip.Pattern {
+			// TODO: the calculation of the percentage is currently very basic and may not be correct.
+
+			if options.Print.InfillPercent != 0 {
+				mm10 := data.Millimeter(10).ToMicrometer()
+				linesPer10mmFor100Percent := mm10 / extrusionWidth
+				linesPer10mmForInfillPercent := float64(linesPer10mmFor100Percent) * float64(options.Print.InfillPercent) / 100.0
+
+				lineWidth := data.Micrometer(float64(mm10) / linesPer10mmForInfillPercent)
+
+				pattern := clip.NewLinearPattern(extrusionWidth, lineWidth, min, max, options.Print.InfillRotationDegree)
+				if options.Print.InfillBorder {
+					pattern = clip.NewBorderedPattern(pattern, extrusionWidth)
+				}
+				if options.Print.InfillChaining {
+					return clip.NewChainedPattern(pattern)
+				}
+				return pattern
+			}
+
+			return nil
+		}
 	}
 
 	s.reader = reader.Reader(&options)
@@ -51,33 +82,22 @@ func NewGoSlice(options data.Options) *GoSlice {
 		gcode.WithRenderer(renderer.PreLayer{}),
 		gcode.WithRenderer(renderer.Perimeter{}),
 		gcode.WithRenderer(&renderer.Infill{
-			PatternSetup: topBottomPatternFactory,
-			AttrName:     "bottom",
-			Comments:     []string{"TYPE:FILL", "BOTTOM-FILL"},
+			PatternSetup:             topBottomPatternFactory(options.Printer.ExtrusionWidth),
+			InitialLayerPatternSetup: topBottomPatternFactory(options.LayerExtrusionWidth(0)),
+			AttrName:                 "bottom",
+			Comments:                 []string{"TYPE:FILL", "BOTTOM-FILL"},
 		}),
 		gcode.WithRenderer(&renderer.Infill{
-			PatternSetup: topBottomPatternFactory,
-			AttrName:     "top",
-			Comments:     []string{"TYPE:FILL", "TOP-FILL"},
+			PatternSetup:             topBottomPatternFactory(options.Printer.ExtrusionWidth),
+			InitialLayerPatternSetup: topBottomPatternFactory(options.LayerExtrusionWidth(0)),
+			AttrName:                 "top",
+			Comments:                 []string{"TYPE:FILL", "TOP-FILL"},
 		}),
 		gcode.WithRenderer(&renderer.Infill{
-			PatternSetup: func(min data.MicroPoint, max data.MicroPoint) clip.Pattern {
-				// TODO: the calculation of the percentage is currently very basic and may not be correct.
-
-				if options.Print.InfillPercent != 0 {
-					mm10 := data.Millimeter(10).ToMicrometer()
-					linesPer10mmFor100Percent := mm10 / options.Printer.ExtrusionWidth
-					linesPer10mmForInfillPercent := float64(linesPer10mmFor100Percent) * float64(options.Print.InfillPercent) / 100.0
-
-					lineWidth := data.Micrometer(float64(mm10) / linesPer10mmForInfillPercent)
-
-					return clip.NewLinearPattern(options.Printer.ExtrusionWidth, lineWidth, min, max, options.Print.InfillRotationDegree)
-				}
-
-				return nil
-			},
-			AttrName: "infill",
-			Comments: []string{"TYPE:FILL", "INTERNAL-FILL"},
+			PatternSetup:             infillPatternFactory(options.Printer.ExtrusionWidth),
+			InitialLayerPatternSetup: infillPatternFactory(options.LayerExtrusionWidth(0)),
+			AttrName:                 "infill",
+			Comments:                 []string{"TYPE:FILL", "INTERNAL-FILL"},
 		}),
 		gcode.WithRenderer(renderer.PostLayer{}),
 	)
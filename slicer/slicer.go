@@ -7,7 +7,7 @@ import (
 	"GoSlice/clip"
 	"GoSlice/data"
 	"GoSlice/handler"
-	"errors"
+	"context"
 	"fmt"
 )
 
@@ -101,12 +101,17 @@ func (s slicer) Slice(m data.OptimizedModel) ([]data.PartitionedLayer, error) {
 	retLayers := make([]data.PartitionedLayer, len(layers))
 	c := clip.NewClipper()
 
+	var opts []clip.Option
+	if s.options.PointFilter == "deviation" {
+		opts = append(opts, clip.WithPointFilter(s.options.PointFilterDistance, s.options.PointFilterDeviation))
+	}
+
 	for i, layer := range layers {
 		layer.makePolygons(m, s.options.JoinPolygonSnapDistance, s.options.FinishPolygonSnapDistance)
-		lp, ok := c.GenerateLayerParts(layer)
+		lp, err := c.GenerateLayerParts(context.Background(), layer, opts...)
 
-		if !ok {
-			return nil, errors.New(fmt.Sprintf("partitioning failed at layer %v", i))
+		if err != nil {
+			return nil, fmt.Errorf("partitioning failed at layer %v: %w", i, err)
 		}
 
 		retLayers[i] = lp